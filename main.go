@@ -1,18 +1,36 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"go-task-tracker/repository"
 	"go-task-tracker/server"
 	"go-task-tracker/service"
 	"log/slog"
+	"net"
 	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+)
+
+const (
+	maxAttachmentSize      = 10 << 20
+	maxTaskAttachmentsSize = 50 << 20
+	archiveAfter           = 30 * 24 * time.Hour
+	archiveInterval        = time.Hour
+	shutdownTimeout        = 10 * time.Second
 )
 
 func main() {
 
 	log := slog.New(slog.NewTextHandler(os.Stdout, nil))
 
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
 	filename := "task_list.json"
 	repo, err := repository.NewTaskRepositoryFile(filename)
 	if err != nil {
@@ -20,14 +38,117 @@ func main() {
 		panic(err)
 	}
 
+	repositories := service.Repositories{
+		Tasks:       &repo,
+		Users:       mustOpen(log, repository.NewUserRepositoryFile, "user_list.json"),
+		Comments:    mustOpen(log, repository.NewCommentRepositoryFile, "comment_list.json"),
+		Attachments: mustOpen(log, repository.NewAttachmentRepositoryFile, "attachment_list.json"),
+		Blobs:       mustOpen(log, repository.NewBlobStoreDisk, "blobs"),
+		WorkLogs:    mustOpen(log, repository.NewWorkLogRepositoryFile, "worklog_list.json"),
+		Projects:    mustOpen(log, repository.NewProjectRepositoryFile, "project_list.json"),
+		History:     mustOpen(log, repository.NewHistoryRepositoryFile, "history_list.json"),
+		Sprints:     mustOpen(log, repository.NewSprintRepositoryFile, "sprint_list.json"),
+	}
+
+	archive := mustOpen(log, repository.NewTaskArchiveDir, "archive")
+	lastArchivedId, err := archive.LastTaskId()
+	if err != nil {
+		log.Error("failed to start app", slog.String("error", err.Error()))
+		panic(err)
+	}
+	repo.ReserveIds(lastArchivedId)
+	repositories.Archive = archive
+
 	log.Info("Initialized app using file.", slog.String("file", filename))
-	s := service.NewTaskService(&repo, log)
+	s := service.NewTaskService(repositories, service.Config{
+		MaxAttachmentSize:      maxAttachmentSize,
+		MaxTaskAttachmentsSize: maxTaskAttachmentsSize,
+		ArchiveAfter:           archiveAfter,
+	}, log)
+
+	if err = s.RebuildSearchIndex(); err != nil {
+		log.Error("failed to start app", slog.String("error", err.Error()))
+		panic(err)
+	}
+
+	if err = s.CollectGarbage(); err != nil {
+		log.Error("failed to collect unreferenced blobs", slog.String("error", err.Error()))
+	}
+	var archiving sync.WaitGroup
+	archiving.Add(1)
+	go func() {
+		defer archiving.Done()
+		archiveTasks(ctx, &s, log)
+	}()
 	_ = server.NewTaskHandler(s, log)
 
-	log.Info("Server started on port 8080")
-	if err = http.ListenAndServe("127.0.0.1:8080", nil); err != nil {
+	userService := service.NewUserService(repositories.Users, log)
+	_ = server.NewUserHandler(userService, log)
+
+	projectService := service.NewProjectService(repositories, log)
+	_ = server.NewProjectHandler(projectService, log)
+
+	sprintService := service.NewSprintService(repositories, log)
+	_ = server.NewSprintHandler(sprintService, log)
+
+	httpServer := &http.Server{
+		Addr:    "127.0.0.1:8080",
+		Handler: server.Authenticate(userService, log, server.Localize(log, http.DefaultServeMux)),
+		// requests are cancelled on shutdown, which ends the event streams left open
+		BaseContext: func(net.Listener) context.Context { return ctx },
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Info("Server started on port 8080")
+		serverErr <- httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err = <-serverErr:
 		log.Error("failed to start server", slog.String("error", err.Error()))
 		panic(err)
+	case <-ctx.Done():
 	}
 
+	log.Info("Shutting down server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err = httpServer.Shutdown(shutdownCtx); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		log.Error("failed to shut down server", slog.String("error", err.Error()))
+	}
+
+	// an archive run in progress is finished so no task is left half archived
+	archiving.Wait()
+	log.Info("Server stopped")
+}
+
+// mustOpen opens the store at path with open, stopping the app when it fails.
+func mustOpen[T any](log *slog.Logger, open func(string) (T, error), path string) T {
+	store, err := open(path)
+	if err != nil {
+		log.Error("failed to start app", slog.String("path", path), slog.String("error", err.Error()))
+		panic(err)
+	}
+	log.Info("Opened store.", slog.String("path", path))
+	return store
+}
+
+// archiveTasks archives the tasks done for long enough now and then every archiveInterval,
+// until ctx is done.
+func archiveTasks(ctx context.Context, s *service.TaskService, log *slog.Logger) {
+	ticker := time.NewTicker(archiveInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := s.ArchiveTasks(); err != nil {
+			log.Error("failed to archive tasks", slog.String("error", err.Error()))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
 }
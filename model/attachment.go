@@ -0,0 +1,14 @@
+package model
+
+// Attachment describes a file attached to a task, its content is kept in a blob store
+// under its SHA-256 Hash so identical files are stored once.
+type Attachment struct {
+	Id          int      `json:"Id"`
+	TaskId      int      `json:"TaskId"`
+	Filename    string   `json:"Filename"`
+	ContentType string   `json:"ContentType"`
+	Size        int64    `json:"Size"`
+	Hash        string   `json:"Hash"`
+	UploaderId  int      `json:"UploaderId,omitempty"`
+	CreatedAt   DateTime `json:"CreatedAt"`
+}
@@ -0,0 +1,38 @@
+package model
+
+// ChecklistItem is a step of a task that is checked off independently.
+type ChecklistItem struct {
+	Id        int       `json:"Id"`
+	Text      string    `json:"Text"`
+	Checked   bool      `json:"Checked"`
+	CheckedAt *DateTime `json:"CheckedAt,omitempty"`
+}
+
+type CreateChecklistItem struct {
+	Text string `json:"text"`
+}
+
+// ReorderChecklist lists the ids of every checklist item of a task in their new order.
+type ReorderChecklist struct {
+	Ids []int `json:"ids"`
+}
+
+type ChecklistProgress struct {
+	Checked int `json:"Checked"`
+	Total   int `json:"Total"`
+}
+
+// Progress returns how many items of checklist are checked, nil for an empty checklist.
+func Progress(checklist []ChecklistItem) *ChecklistProgress {
+	if len(checklist) == 0 {
+		return nil
+	}
+
+	progress := ChecklistProgress{Total: len(checklist)}
+	for _, item := range checklist {
+		if item.Checked {
+			progress.Checked++
+		}
+	}
+	return &progress
+}
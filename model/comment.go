@@ -0,0 +1,18 @@
+package model
+
+type Comment struct {
+	Id        int      `json:"Id"`
+	TaskId    int      `json:"TaskId"`
+	AuthorId  int      `json:"AuthorId"`
+	Body      string   `json:"Body"`
+	CreatedAt DateTime `json:"CreatedAt"`
+	UpdatedAt DateTime `json:"UpdatedAt"`
+}
+
+type CreateComment struct {
+	Body string `json:"body"`
+}
+
+type UpdateComment struct {
+	Body string `json:"body"`
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"cmp"
+	"fmt"
+	"math"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type FieldType string
+
+const (
+	FieldString FieldType = "string"
+	FieldNumber FieldType = "number"
+	FieldEnum   FieldType = "enum"
+	// FieldDate values are dates formatted as 2006-01-02.
+	FieldDate FieldType = "date"
+	// FieldUser values are user ids.
+	FieldUser FieldType = "user"
+)
+
+var fieldNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]{0,31}$`)
+
+// FieldDefinition describes a custom field the tasks of a project can have.
+type FieldDefinition struct {
+	Name string    `json:"Name"`
+	Type FieldType `json:"Type"`
+	// Options lists the values allowed for an enum field.
+	Options  []string `json:"Options,omitempty"`
+	Required bool     `json:"Required,omitempty"`
+}
+
+// Validate reports whether d is a usable field definition.
+func (d FieldDefinition) Validate() error {
+	if !fieldNameRegexp.MatchString(d.Name) {
+		return fmt.Errorf("field name %q must be up to 32 lowercase letters, digits or underscores, starting with a letter", d.Name)
+	}
+
+	switch d.Type {
+	case FieldString, FieldNumber, FieldDate, FieldUser:
+		if len(d.Options) > 0 {
+			return fmt.Errorf("only enum fields have options, field %s is a %s", d.Name, d.Type)
+		}
+	case FieldEnum:
+		if len(d.Options) == 0 {
+			return fmt.Errorf("enum field %s needs options", d.Name)
+		}
+	default:
+		return fmt.Errorf("unknown type %q of field %s", d.Type, d.Name)
+	}
+	return nil
+}
+
+// Normalize checks that value, as decoded from JSON, fits the type of d and returns it in
+// the form stored on tasks: numbers and user ids as float64, the other types as strings.
+func (d FieldDefinition) Normalize(value any) (any, error) {
+	switch d.Type {
+	case FieldString:
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+	case FieldNumber:
+		if n, ok := numberValue(value); ok {
+			return n, nil
+		}
+	case FieldEnum:
+		if s, ok := value.(string); ok {
+			if !slices.Contains(d.Options, s) {
+				return nil, fmt.Errorf("field %s must be one of %s", d.Name, strings.Join(d.Options, ", "))
+			}
+			return s, nil
+		}
+	case FieldDate:
+		if s, ok := value.(string); ok {
+			if _, err := time.Parse(time.DateOnly, s); err != nil {
+				return nil, fmt.Errorf("field %s must be a date formatted as %s", d.Name, time.DateOnly)
+			}
+			return s, nil
+		}
+	case FieldUser:
+		if n, ok := numberValue(value); ok && n > 0 && n == math.Trunc(n) {
+			return n, nil
+		}
+	}
+	return nil, fmt.Errorf("field %s must be a %s", d.Name, d.Type)
+}
+
+// Field returns the definition of the custom field name of p.
+func (p Project) Field(name string) (FieldDefinition, bool) {
+	index := slices.IndexFunc(p.Fields, func(d FieldDefinition) bool { return d.Name == name })
+	if index == -1 {
+		return FieldDefinition{}, false
+	}
+	return p.Fields[index], true
+}
+
+// FormatFieldValue returns a custom field value as written in query params.
+func FormatFieldValue(value any) string {
+	if n, ok := numberValue(value); ok {
+		return strconv.FormatFloat(n, 'f', -1, 64)
+	}
+	return fmt.Sprint(value)
+}
+
+// CompareFieldValues orders custom field values, numbers by value and anything else by
+// its text, which orders dates chronologically.
+func CompareFieldValues(a any, b any) int {
+	na, okA := numberValue(a)
+	nb, okB := numberValue(b)
+	if okA && okB {
+		return cmp.Compare(na, nb)
+	}
+	return cmp.Compare(FormatFieldValue(a), FormatFieldValue(b))
+}
+
+func numberValue(value any) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
@@ -0,0 +1,69 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestFieldDefinitionNormalize(t *testing.T) {
+
+	var testTable = []struct {
+		field    FieldDefinition
+		value    any
+		expected any
+		valid    bool
+	}{
+		{FieldDefinition{Name: "customer", Type: FieldString}, "acme", "acme", true},
+		{FieldDefinition{Name: "customer", Type: FieldString}, 3.0, nil, false},
+		{FieldDefinition{Name: "score", Type: FieldNumber}, 2.5, 2.5, true},
+		{FieldDefinition{Name: "score", Type: FieldNumber}, 4, 4.0, true},
+		{FieldDefinition{Name: "score", Type: FieldNumber}, "4", nil, false},
+		{FieldDefinition{Name: "severity", Type: FieldEnum, Options: []string{"low", "high"}}, "high", "high", true},
+		{FieldDefinition{Name: "severity", Type: FieldEnum, Options: []string{"low", "high"}}, "mid", nil, false},
+		{FieldDefinition{Name: "seen", Type: FieldDate}, "2026-01-07", "2026-01-07", true},
+		{FieldDefinition{Name: "seen", Type: FieldDate}, "07/01/2026", nil, false},
+		{FieldDefinition{Name: "owner", Type: FieldUser}, 7.0, 7.0, true},
+		{FieldDefinition{Name: "owner", Type: FieldUser}, 1.5, nil, false},
+		{FieldDefinition{Name: "owner", Type: FieldUser}, 0.0, nil, false},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%s %v), Expect: %v", testData.field.Type, testData.value, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			answer, err := testData.field.Normalize(testData.value)
+			if testData.valid != (err == nil) {
+				t.Fatalf("with input (%s %v) got error %v", testData.field.Type, testData.value, err)
+			}
+			if answer != testData.expected {
+				t.Errorf("with input (%s %v) got %v, but expected %v", testData.field.Type, testData.value, answer, testData.expected)
+			}
+		})
+	}
+}
+
+func TestFieldDefinitionValidate(t *testing.T) {
+
+	var testTable = []struct {
+		field FieldDefinition
+		valid bool
+	}{
+		{FieldDefinition{Name: "severity", Type: FieldEnum, Options: []string{"low"}}, true},
+		{FieldDefinition{Name: "severity", Type: FieldEnum}, false},
+		{FieldDefinition{Name: "customer", Type: FieldString, Options: []string{"acme"}}, false},
+		{FieldDefinition{Name: "Customer", Type: FieldString}, false},
+		{FieldDefinition{Name: "customer", Type: "text"}, false},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%+v), Expect valid: %t", testData.field, testData.valid)
+
+		t.Run(testName, func(t *testing.T) {
+			if err := testData.field.Validate(); testData.valid != (err == nil) {
+				t.Errorf("with input (%+v) got error %v", testData.field, err)
+			}
+		})
+	}
+}
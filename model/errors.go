@@ -0,0 +1,18 @@
+package model
+
+import "errors"
+
+// Sentinel errors telling what kind of failure an error is, checked with errors.Is.
+// Stores and services wrap them so the kind survives the layers an error goes through.
+var (
+	// ErrNotFound means a record doesn't exist.
+	ErrNotFound = errors.New("not found")
+	// ErrConflict means a change clashes with the current state, such as a key in use.
+	ErrConflict = errors.New("conflict")
+	// ErrValidation means the input of an operation is invalid.
+	ErrValidation = errors.New("invalid input")
+	// ErrForbidden means the user isn't allowed to do what was asked.
+	ErrForbidden = errors.New("forbidden")
+	// ErrUnavailable means the storage couldn't be reached, retrying later may work.
+	ErrUnavailable = errors.New("storage unavailable")
+)
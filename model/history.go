@@ -0,0 +1,39 @@
+package model
+
+type RevisionType string
+
+const (
+	TaskCreated RevisionType = "created"
+	TaskUpdated RevisionType = "updated"
+	TaskMoved   RevisionType = "moved"
+	TaskDeleted RevisionType = "deleted"
+	// TaskArchived records a done task moving out of the task list into the archive.
+	TaskArchived RevisionType = "archived"
+)
+
+// TaskRevision records a change made to a task along with the state of the task right
+// after it, so the task can be reconstructed as it was at any point in time.
+type TaskRevision struct {
+	Id      int          `json:"Id"`
+	TaskId  int          `json:"TaskId"`
+	Type    RevisionType `json:"Type"`
+	ActorId int          `json:"ActorId,omitempty"`
+	// Fields are the names of the fields changed, empty for created and deleted tasks.
+	Fields []string `json:"Fields,omitempty"`
+	Task   Task     `json:"Task"`
+	At     DateTime `json:"At"`
+}
+
+// TaskEvent notifies a change made to a task as it happens. Type is TaskCreated,
+// TaskUpdated or TaskDeleted, tasks moved are updated and tasks archived are deleted from
+// the task list.
+type TaskEvent struct {
+	// Id orders the events of a stream, subscribers resume after it.
+	Id      string       `json:"Id"`
+	Type    RevisionType `json:"Type"`
+	ActorId int          `json:"ActorId,omitempty"`
+	// Fields are the names of the fields changed, empty for created and deleted tasks.
+	Fields []string `json:"Fields,omitempty"`
+	Task   Task     `json:"Task"`
+	At     DateTime `json:"At"`
+}
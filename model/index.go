@@ -0,0 +1,44 @@
+package model
+
+import (
+	"strconv"
+)
+
+// TaskIndex is a task field the task repository keeps an index of, so the tasks with a
+// given value are found without reading every task.
+type TaskIndex string
+
+const (
+	IndexStatus   TaskIndex = "status"
+	IndexAssignee TaskIndex = "assignee"
+	IndexProject  TaskIndex = "project"
+	IndexSprint   TaskIndex = "sprint"
+	IndexTag      TaskIndex = "tag"
+)
+
+// TaskIndexes lists every TaskIndex.
+var TaskIndexes = []TaskIndex{IndexStatus, IndexAssignee, IndexProject, IndexSprint, IndexTag}
+
+// Keys returns the keys task is found under in the index i: status names, ids with 0
+// for none, and tags.
+func (i TaskIndex) Keys(task Task) []string {
+	switch i {
+	case IndexStatus:
+		return []string{task.Status.Name()}
+	case IndexAssignee:
+		return []string{strconv.Itoa(task.AssigneeId)}
+	case IndexProject:
+		return []string{strconv.Itoa(task.ProjectId)}
+	case IndexSprint:
+		return []string{strconv.Itoa(task.SprintId)}
+	case IndexTag:
+		return task.Tags
+	}
+	return nil
+}
+
+// TaskLookup selects the tasks found under any of Keys in Index.
+type TaskLookup struct {
+	Index TaskIndex
+	Keys  []string
+}
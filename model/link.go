@@ -0,0 +1,52 @@
+package model
+
+// LinkType is the relation a task has to a linked task. Every type has an inverse, stored
+// on the linked task, so links can be followed from both ends.
+type LinkType string
+
+const (
+	RelatesTo    LinkType = "relates_to"
+	Duplicates   LinkType = "duplicates"
+	DuplicatedBy LinkType = "duplicated_by"
+	Causes       LinkType = "causes"
+	CausedBy     LinkType = "caused_by"
+	Blocks       LinkType = "blocks"
+	BlockedBy    LinkType = "blocked_by"
+)
+
+var linkInverses = map[LinkType]LinkType{
+	RelatesTo:    RelatesTo,
+	Duplicates:   DuplicatedBy,
+	DuplicatedBy: Duplicates,
+	Causes:       CausedBy,
+	CausedBy:     Causes,
+	Blocks:       BlockedBy,
+	BlockedBy:    Blocks,
+}
+
+// Inverse returns the type of the link stored on the other end of a link of type t.
+func (t LinkType) Inverse() LinkType {
+	return linkInverses[t]
+}
+
+func (t LinkType) Valid() bool {
+	_, ok := linkInverses[t]
+	return ok
+}
+
+// TaskLink relates the task it is stored on to the task TaskId.
+type TaskLink struct {
+	Type   LinkType `json:"Type"`
+	TaskId int      `json:"TaskId"`
+}
+
+// LinkTask is the request to add or remove a link to the task TaskId.
+type LinkTask struct {
+	Type   LinkType `json:"type"`
+	TaskId int      `json:"taskId"`
+}
+
+// ResolveDuplicate is the request to close a task as a duplicate of the task TaskId.
+type ResolveDuplicate struct {
+	TaskId int `json:"taskId"`
+}
@@ -0,0 +1,49 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// MergePatch applies patch to the JSON document target as described by RFC 7396. Members
+// of a patch object replace the members of the target with the same name, objects are
+// merged recursively and null removes the member. A patch that isn't an object replaces
+// the whole target.
+func MergePatch(target []byte, patch []byte) ([]byte, error) {
+	var patchValue any
+	if err := json.Unmarshal(patch, &patchValue); err != nil {
+		return nil, fmt.Errorf("failed to decode merge patch: %w", err)
+	}
+
+	var targetValue any
+	if err := json.Unmarshal(target, &targetValue); err != nil {
+		return nil, fmt.Errorf("failed to decode merge patch target: %w", err)
+	}
+
+	merged, err := json.Marshal(mergeValue(targetValue, patchValue))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode merged document: %w", err)
+	}
+	return merged, nil
+}
+
+func mergeValue(target any, patch any) any {
+	patchObject, ok := patch.(map[string]any)
+	if !ok {
+		return patch
+	}
+
+	targetObject, ok := target.(map[string]any)
+	if !ok {
+		targetObject = make(map[string]any, len(patchObject))
+	}
+
+	for name, value := range patchObject {
+		if value == nil {
+			delete(targetObject, name)
+		} else {
+			targetObject[name] = mergeValue(targetObject[name], value)
+		}
+	}
+	return targetObject
+}
@@ -0,0 +1,64 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"testing"
+)
+
+func TestMergePatch(t *testing.T) {
+
+	// The examples of RFC 7396, appendix A.
+	var testTable = []struct {
+		target   string
+		patch    string
+		expected string
+	}{
+		{`{"a":"b"}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"b"}`, `{"b":"c"}`, `{"a":"b","b":"c"}`},
+		{`{"a":"b"}`, `{"a":null}`, `{}`},
+		{`{"a":"b","b":"c"}`, `{"a":null}`, `{"b":"c"}`},
+		{`{"a":["b"]}`, `{"a":"c"}`, `{"a":"c"}`},
+		{`{"a":"c"}`, `{"a":["b"]}`, `{"a":["b"]}`},
+		{`{"a":{"b":"c"}}`, `{"a":{"b":"d","c":null}}`, `{"a":{"b":"d"}}`},
+		{`{"a":[{"b":"c"}]}`, `{"a":[1]}`, `{"a":[1]}`},
+		{`["a","b"]`, `["c","d"]`, `["c","d"]`},
+		{`{"a":"b"}`, `["c"]`, `["c"]`},
+		{`{"a":"foo"}`, `null`, `null`},
+		{`{"a":"foo"}`, `"bar"`, `"bar"`},
+		{`{"e":null}`, `{"a":1}`, `{"a":1,"e":null}`},
+		{`[1,2]`, `{"a":"b","c":null}`, `{"a":"b"}`},
+		{`{}`, `{"a":{"bb":{"ccc":null}}}`, `{"a":{"bb":{}}}`},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%s, %s), Expect: %s", testData.target, testData.patch, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			answer, err := MergePatch([]byte(testData.target), []byte(testData.patch))
+			if err != nil {
+				t.Fatalf("expected MergePatch call to return no errors, got \"%s\"", err)
+			}
+
+			var got, expected any
+			if err := json.Unmarshal(answer, &got); err != nil {
+				t.Fatalf("failed to decode merged document %s: %s", answer, err)
+			}
+			if err := json.Unmarshal([]byte(testData.expected), &expected); err != nil {
+				t.Fatalf("failed to decode expected document: %s", err)
+			}
+
+			if !reflect.DeepEqual(got, expected) {
+				t.Errorf("with input (%s, %s) got %s, but expected %s", testData.target, testData.patch, answer, testData.expected)
+			}
+		})
+	}
+}
+
+func TestMergePatch_InvalidPatch(t *testing.T) {
+	if _, err := MergePatch([]byte(`{"a":"b"}`), []byte(`{"a":`)); err == nil {
+		t.Errorf("expected MergePatch call with invalid JSON to return an error")
+	}
+}
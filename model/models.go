@@ -2,6 +2,10 @@ package model
 
 import (
 	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 	"time"
 )
@@ -22,40 +26,591 @@ func (t TaskStatus) EnumIndex() int {
 	return int(t)
 }
 
-type DateTime time.Time
+var taskStatusNames = [...]string{"todo", "in_progress", "done"}
 
-func (t *DateTime) String() string {
-	return fmt.Sprintf(`"%s"`, time.Time(*t).Format("2006-01-02 15:04:05"))
+// Name returns the name t is written as in JSON and query params, Unknown(n) for a
+// status that isn't Valid.
+func (t TaskStatus) Name() string {
+	if !t.Valid() {
+		return fmt.Sprintf("Unknown(%d)", int(t))
+	}
+	return taskStatusNames[t]
+}
+
+func (t TaskStatus) Valid() bool {
+	return t >= TODO && t <= Done
+}
+
+// ParseTaskStatus reads a status from its name, or from its number as statuses were
+// written before they had names.
+func ParseTaskStatus(value string) (TaskStatus, error) {
+	for i, name := range taskStatusNames {
+		if value == name {
+			return TaskStatus(i), nil
+		}
+	}
+
+	number, err := strconv.Atoi(value)
+	if err != nil || !TaskStatus(number).Valid() {
+		return 0, fmt.Errorf("invalid status %q, expected one of %s", value, strings.Join(taskStatusNames[:], ", "))
+	}
+	return TaskStatus(number), nil
 }
 
-func (t *DateTime) MarshalJSON() ([]byte, error) {
-	stamp := fmt.Sprintf(`"%s"`, time.Time(*t).Format("2006-01-02 15:04:05"))
-	return []byte(stamp), nil
+func (t TaskStatus) MarshalJSON() ([]byte, error) {
+	if !t.Valid() {
+		return nil, fmt.Errorf("invalid status %d", t)
+	}
+	return []byte(`"` + t.Name() + `"`), nil
 }
 
-func (t *DateTime) UnmarshalJSON(b []byte) (err error) {
-	date, err := time.Parse("2006-01-02 15:04:05", strings.Replace(string(b), `"`, "", 2))
+// UnmarshalJSON accepts status names as well as legacy numbers, rejecting unknown statuses.
+func (t *TaskStatus) UnmarshalJSON(b []byte) error {
+	if string(b) == "null" {
+		return nil
+	}
+
+	status, err := ParseTaskStatus(strings.Trim(string(b), `"`))
 	if err != nil {
 		return err
 	}
-	*t = DateTime(date)
-	return
+	*t = status
+	return nil
 }
 
+// DateTime is a point in time, stored in UTC and written in RFC 3339.
+type DateTime time.Time
+
+// legacyDateTimeLayout is the zone-less layout timestamps were written in before RFC 3339,
+// always in the local time of the server.
+const legacyDateTimeLayout = "2006-01-02 15:04:05"
+
+// Now returns the current time in UTC.
+func Now() DateTime {
+	return DateTime(time.Now().UTC())
+}
+
+func (t DateTime) String() string {
+	return time.Time(t).Format(time.RFC3339)
+}
+
+func (t DateTime) MarshalJSON() ([]byte, error) {
+	return []byte(`"` + time.Time(t).Format(time.RFC3339) + `"`), nil
+}
+
+// UnmarshalJSON reads RFC 3339 timestamps, and zone-less legacy ones as local time.
+func (t *DateTime) UnmarshalJSON(b []byte) error {
+	value := strings.Trim(string(b), `"`)
+	date, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		var legacyErr error
+		if date, legacyErr = time.ParseInLocation(legacyDateTimeLayout, value, time.Local); legacyErr != nil {
+			return err
+		}
+	}
+	*t = DateTime(date.UTC())
+	return nil
+}
+
+// IsLegacyDateTime reports whether b holds a timestamp in the zone-less legacy layout.
+func IsLegacyDateTime(b []byte) bool {
+	return legacyDateTimeRegexp.Match(b)
+}
+
+var legacyDateTimeRegexp = regexp.MustCompile(`"\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}"`)
+
 type Task struct {
-	Id          int        `json:"Id"`
-	Description string     `json:"Description"`
-	Status      TaskStatus `json:"Status"`
-	CreatedAt   DateTime   `json:"CreatedAt"`
-	UpdatedAt   DateTime   `json:"UpdatedAt"`
+	Id int `json:"Id"`
+	// Key identifies the task within its project, such as OPS-42. Tasks outside of a
+	// project have no key.
+	Key          string      `json:"Key,omitempty"`
+	ProjectId    int         `json:"ProjectId,omitempty"`
+	PreviousKeys []string    `json:"PreviousKeys,omitempty"`
+	SprintId     int         `json:"SprintId,omitempty"`
+	Description  string      `json:"Description"`
+	Status       TaskStatus  `json:"Status"`
+	AssigneeId   int         `json:"AssigneeId,omitempty"`
+	ReporterId   int         `json:"ReporterId,omitempty"`
+	DueAt        *DateTime   `json:"DueAt,omitempty"`
+	Recurrence   *Recurrence `json:"Recurrence,omitempty"`
+	Tags         []string    `json:"Tags,omitempty"`
+	StoryPoints  int         `json:"StoryPoints,omitempty"`
+	// OriginalEstimate is the work expected when the task was planned and RemainingEstimate
+	// what is left of it, which decreases as work is logged.
+	OriginalEstimate  Duration        `json:"OriginalEstimate,omitempty"`
+	RemainingEstimate Duration        `json:"RemainingEstimate,omitempty"`
+	Checklist         []ChecklistItem `json:"Checklist,omitempty"`
+	// CompleteWithChecklist moves the task to Done once every checklist item is checked.
+	CompleteWithChecklist bool `json:"CompleteWithChecklist,omitempty"`
+	// CustomFields holds values of the custom fields defined by the project of the task.
+	CustomFields map[string]any `json:"CustomFields,omitempty"`
+	Links        []TaskLink     `json:"Links,omitempty"`
+	// DuplicateOf is the task this task was closed as a duplicate of.
+	DuplicateOf int `json:"DuplicateOf,omitempty"`
+	// StartedAt is when the task first moved to InProgress, nil for tasks never started.
+	StartedAt *DateTime `json:"StartedAt,omitempty"`
+	// CompletedAt is when the task last moved to Done, nil for tasks that aren't done.
+	CompletedAt *DateTime `json:"CompletedAt,omitempty"`
+	CreatedAt   DateTime  `json:"CreatedAt"`
+	UpdatedAt   DateTime  `json:"UpdatedAt"`
+}
+
+// CompletionTime returns when t was completed, falling back to its last update for tasks
+// completed before completion times were recorded.
+func (t Task) CompletionTime() time.Time {
+	if t.CompletedAt != nil {
+		return time.Time(*t.CompletedAt)
+	}
+	return time.Time(t.UpdatedAt)
 }
 
 type CreateTask struct {
-	Description string     `json:"description"`
-	Status      TaskStatus `json:"status"`
+	// Project is the key of the project the task is created in.
+	Project     string      `json:"project"`
+	SprintId    int         `json:"sprintId"`
+	Description string      `json:"description"`
+	Status      TaskStatus  `json:"status"`
+	AssigneeId  int         `json:"assigneeId"`
+	DueAt       *DateTime   `json:"dueAt"`
+	Recurrence  *Recurrence `json:"recurrence"`
+	Tags        []string    `json:"tags"`
+	StoryPoints int         `json:"storyPoints"`
+	// RemainingEstimate defaults to OriginalEstimate.
+	OriginalEstimate  Duration  `json:"originalEstimate"`
+	RemainingEstimate *Duration `json:"remainingEstimate"`
+	// Checklist holds the text of the checklist items to create with the task.
+	Checklist             []string       `json:"checklist"`
+	CompleteWithChecklist bool           `json:"completeWithChecklist"`
+	CustomFields          map[string]any `json:"customFields"`
 }
 
+// UpdateTask holds the fields to change on a task, nil fields are left untouched.
+// An AssigneeId of 0 unassigns the task, a zero DueAt clears the due date and a
+// Recurrence without Frequency stops it.
 type UpdateTask struct {
-	Description *string     `json:"description"`
-	Status      *TaskStatus `json:"status"`
+	Description       *string     `json:"description"`
+	Status            *TaskStatus `json:"status"`
+	AssigneeId        *int        `json:"assigneeId"`
+	DueAt             *DateTime   `json:"dueAt"`
+	Recurrence        *Recurrence `json:"recurrence"`
+	Tags              *[]string   `json:"tags"`
+	StoryPoints       *int        `json:"storyPoints"`
+	OriginalEstimate  *Duration   `json:"originalEstimate"`
+	RemainingEstimate *Duration   `json:"remainingEstimate"`
+	// SprintId of 0 removes the task from its sprint.
+	SprintId              *int  `json:"sprintId"`
+	CompleteWithChecklist *bool `json:"completeWithChecklist"`
+	// CustomFields sets the given custom fields, a null value removes the field.
+	CustomFields map[string]any `json:"customFields"`
+	// Checklist is changed through the checklist endpoints only.
+	Checklist *[]ChecklistItem `json:"-"`
+
+	// Links and DuplicateOf are changed through the link endpoints only.
+	Links       *[]TaskLink `json:"-"`
+	DuplicateOf *int        `json:"-"`
+	// StartedAt and CompletedAt are set by the service when the status changes, a zero
+	// CompletedAt clears it.
+	StartedAt   *DateTime `json:"-"`
+	CompletedAt *DateTime `json:"-"`
+
+	// The fields below are set by the service when moving a task between projects.
+	ProjectId    *int      `json:"-"`
+	Key          *string   `json:"-"`
+	PreviousKeys *[]string `json:"-"`
+}
+
+// Apply copies every non nil field of u into task.
+func (u UpdateTask) Apply(task *Task) {
+	if u.Description != nil {
+		task.Description = *u.Description
+	}
+
+	if u.Status != nil {
+		task.Status = *u.Status
+	}
+
+	if u.AssigneeId != nil {
+		task.AssigneeId = *u.AssigneeId
+	}
+
+	if u.DueAt != nil {
+		task.DueAt = u.DueAt
+		if time.Time(*u.DueAt).IsZero() {
+			task.DueAt = nil
+		}
+	}
+
+	if u.Recurrence != nil {
+		task.Recurrence = u.Recurrence
+		if u.Recurrence.Frequency == "" {
+			task.Recurrence = nil
+		}
+	}
+
+	if u.Tags != nil {
+		task.Tags = *u.Tags
+	}
+
+	if u.StoryPoints != nil {
+		task.StoryPoints = *u.StoryPoints
+	}
+
+	if u.OriginalEstimate != nil {
+		task.OriginalEstimate = *u.OriginalEstimate
+	}
+
+	if u.RemainingEstimate != nil {
+		task.RemainingEstimate = *u.RemainingEstimate
+	}
+
+	if u.SprintId != nil {
+		task.SprintId = *u.SprintId
+	}
+
+	if u.CompleteWithChecklist != nil {
+		task.CompleteWithChecklist = *u.CompleteWithChecklist
+	}
+
+	if u.Checklist != nil {
+		task.Checklist = *u.Checklist
+	}
+
+	if u.CustomFields != nil {
+		fields := maps.Clone(task.CustomFields)
+		if fields == nil {
+			fields = make(map[string]any, len(u.CustomFields))
+		}
+		for name, value := range u.CustomFields {
+			if value == nil {
+				delete(fields, name)
+			} else {
+				fields[name] = value
+			}
+		}
+
+		task.CustomFields = fields
+		if len(fields) == 0 {
+			task.CustomFields = nil
+		}
+	}
+
+	if u.Links != nil {
+		task.Links = *u.Links
+	}
+
+	if u.DuplicateOf != nil {
+		task.DuplicateOf = *u.DuplicateOf
+	}
+
+	if u.StartedAt != nil {
+		task.StartedAt = u.StartedAt
+	}
+
+	if u.CompletedAt != nil {
+		task.CompletedAt = u.CompletedAt
+		if time.Time(*u.CompletedAt).IsZero() {
+			task.CompletedAt = nil
+		}
+	}
+
+	if u.ProjectId != nil {
+		task.ProjectId = *u.ProjectId
+	}
+
+	if u.Key != nil {
+		task.Key = *u.Key
+	}
+
+	if u.PreviousKeys != nil {
+		task.PreviousKeys = *u.PreviousKeys
+	}
+}
+
+// ReplaceTask holds every field of a task a client can edit directly, a task replaced by
+// it loses the values it leaves out. The checklist, links and project of the task have
+// endpoints of their own and are kept.
+type ReplaceTask struct {
+	Description           string         `json:"description"`
+	Status                TaskStatus     `json:"status"`
+	AssigneeId            int            `json:"assigneeId"`
+	DueAt                 *DateTime      `json:"dueAt,omitempty"`
+	Recurrence            *Recurrence    `json:"recurrence,omitempty"`
+	Tags                  []string       `json:"tags,omitempty"`
+	StoryPoints           int            `json:"storyPoints"`
+	OriginalEstimate      Duration       `json:"originalEstimate"`
+	RemainingEstimate     Duration       `json:"remainingEstimate"`
+	SprintId              int            `json:"sprintId"`
+	CompleteWithChecklist bool           `json:"completeWithChecklist"`
+	CustomFields          map[string]any `json:"customFields,omitempty"`
+}
+
+// NewReplaceTask returns the editable fields of task, the document merge patches of the
+// task apply to.
+func NewReplaceTask(task Task) ReplaceTask {
+	return ReplaceTask{
+		Description:           task.Description,
+		Status:                task.Status,
+		AssigneeId:            task.AssigneeId,
+		DueAt:                 task.DueAt,
+		Recurrence:            task.Recurrence,
+		Tags:                  task.Tags,
+		StoryPoints:           task.StoryPoints,
+		OriginalEstimate:      task.OriginalEstimate,
+		RemainingEstimate:     task.RemainingEstimate,
+		SprintId:              task.SprintId,
+		CompleteWithChecklist: task.CompleteWithChecklist,
+		CustomFields:          task.CustomFields,
+	}
+}
+
+// Update returns the UpdateTask turning current into r, setting every field r holds
+// and clearing the custom fields of current r leaves out.
+func (r ReplaceTask) Update(current Task) UpdateTask {
+	dueAt := DateTime{}
+	if r.DueAt != nil {
+		dueAt = *r.DueAt
+	}
+
+	recurrence := Recurrence{}
+	if r.Recurrence != nil {
+		recurrence = *r.Recurrence
+	}
+
+	tags := r.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	customFields := maps.Clone(r.CustomFields)
+	if customFields == nil {
+		customFields = make(map[string]any, len(current.CustomFields))
+	}
+	for name := range current.CustomFields {
+		if _, ok := customFields[name]; !ok {
+			customFields[name] = nil
+		}
+	}
+
+	return UpdateTask{
+		Description:           &r.Description,
+		Status:                &r.Status,
+		AssigneeId:            &r.AssigneeId,
+		DueAt:                 &dueAt,
+		Recurrence:            &recurrence,
+		Tags:                  &tags,
+		StoryPoints:           &r.StoryPoints,
+		OriginalEstimate:      &r.OriginalEstimate,
+		RemainingEstimate:     &r.RemainingEstimate,
+		SprintId:              &r.SprintId,
+		CompleteWithChecklist: &r.CompleteWithChecklist,
+		CustomFields:          customFields,
+	}
+}
+
+// TaskFilter narrows the tasks returned by a listing, zero values match every task.
+type TaskFilter struct {
+	Status      *TaskStatus
+	Description string
+	// Text matches tasks whose description contains it, ignoring case.
+	Text       string
+	AssigneeId int
+	Tag        string
+	// Project is a project key, resolved to ProjectId by the service.
+	Project   string
+	ProjectId int
+	SprintId  int
+	// CustomFields matches tasks whose custom fields have the given values, as formatted by
+	// FormatFieldValue.
+	CustomFields map[string]string
+	// Query is a query in the language of package query, such as
+	// status:todo AND created>2026-01-01.
+	Query string
+	// Location is the time zone of the dates in Query, UTC when nil.
+	Location *time.Location
+	// UserId is the user making the query, whom assignee:me refers to.
+	UserId int
+}
+
+// TaskSort orders a task listing by its keys, later keys breaking the ties of earlier ones.
+type TaskSort []TaskSortKey
+
+// TaskSortKey sorts by Field, which is one of the TaskSortFields or the name of a custom
+// field prefixed with "cf.".
+type TaskSortKey struct {
+	Field      string
+	Descending bool
+}
+
+// TaskSortFields lists the task fields a listing can be sorted by.
+var TaskSortFields = []string{"id", "key", "description", "status", "assigneeId", "reporterId", "sprintId", "dueAt",
+	"storyPoints", "originalEstimate", "remainingEstimate", "createdAt", "updatedAt", "startedAt", "completedAt"}
+
+// ParseTaskSort reads a sort such as "dueAt", "-cf.severity" for a descending sort, or
+// "status,-dueAt" for several keys.
+func ParseTaskSort(value string) (TaskSort, error) {
+	var sort TaskSort
+	for _, field := range strings.Split(value, ",") {
+		key := TaskSortKey{Field: strings.TrimPrefix(field, "-"), Descending: strings.HasPrefix(field, "-")}
+		if !slices.Contains(TaskSortFields, key.Field) && !strings.HasPrefix(key.Field, "cf.") {
+			return nil, fmt.Errorf("can't sort tasks by %q", key.Field)
+		}
+
+		if slices.ContainsFunc(sort, func(k TaskSortKey) bool { return k.Field == key.Field }) {
+			return nil, fmt.Errorf("can't sort tasks by %q more than once", key.Field)
+		}
+		sort = append(sort, key)
+	}
+	return sort, nil
+}
+
+// String writes s back in the format of ParseTaskSort.
+func (s TaskSort) String() string {
+	fields := make([]string, 0, len(s))
+	for _, key := range s {
+		if key.Descending {
+			fields = append(fields, "-"+key.Field)
+		} else {
+			fields = append(fields, key.Field)
+		}
+	}
+	return strings.Join(fields, ",")
+}
+
+// TaskPage selects a page of a task listing: the Limit tasks following the task Cursor
+// points to. A zero Limit selects a page of the default size, an empty Cursor starts from
+// the first one.
+type TaskPage struct {
+	Limit  int
+	Cursor string
+}
+
+// TaskListPage is a page of a task listing. Next is the cursor of the following page, empty
+// on the last page, and Total counts the tasks of every page.
+type TaskListPage struct {
+	Items []TaskListItem
+	Next  string
+	Total int
+}
+
+// TaskListItem is a task as shown in listings, along with values derived from related resources.
+type TaskListItem struct {
+	Task
+	CommentCount      int                `json:"CommentCount"`
+	ChecklistProgress *ChecklistProgress `json:"ChecklistProgress,omitempty"`
+}
+
+// TaskStats summarizes the tasks matching a filter. Tasks and Groups count the tasks in
+// the task list, the other fields also cover archived tasks.
+type TaskStats struct {
+	Tasks  int          `json:"Tasks"`
+	Groups []StatsGroup `json:"Groups,omitempty"`
+	// Created and Completed count the tasks created and completed in each interval of the
+	// requested period, intervals without any included.
+	Created   []StatsInterval `json:"Created"`
+	Completed []StatsInterval `json:"Completed"`
+	// LeadTime measures the tasks completed in the period from their creation and
+	// CycleTime from their start.
+	LeadTime  DurationStats `json:"LeadTime"`
+	CycleTime DurationStats `json:"CycleTime"`
+}
+
+// StatsGroup counts a group of tasks, only the field the tasks were grouped by is set.
+type StatsGroup struct {
+	Status     *TaskStatus `json:"Status,omitempty"`
+	Tag        string      `json:"Tag,omitempty"`
+	AssigneeId *int        `json:"AssigneeId,omitempty"`
+	ProjectId  *int        `json:"ProjectId,omitempty"`
+	Tasks      int         `json:"Tasks"`
+}
+
+// StatsInterval counts the tasks of the day or week starting on Start, a date such as
+// 2026-01-31.
+type StatsInterval struct {
+	Start string `json:"Start"`
+	Tasks int    `json:"Tasks"`
+}
+
+// DurationStats describes how long Tasks tasks took, the percentiles being the durations
+// that many percent of them didn't exceed. Durations are zero when no task was measured.
+type DurationStats struct {
+	Tasks   int      `json:"Tasks"`
+	Average Duration `json:"Average"`
+	P50     Duration `json:"P50"`
+	P75     Duration `json:"P75"`
+	P90     Duration `json:"P90"`
+	P95     Duration `json:"P95"`
+}
+
+// BurndownPoint is the work left at the end of Date, a date such as 2026-01-31: the tasks
+// not done and their story points.
+type BurndownPoint struct {
+	Date        string `json:"Date"`
+	Tasks       int    `json:"Tasks"`
+	StoryPoints int    `json:"StoryPoints"`
+}
+
+// FlowPoint counts the tasks in each status at the end of Date, a date such as 2026-01-31.
+type FlowPoint struct {
+	Date       string `json:"Date"`
+	Todo       int    `json:"Todo"`
+	InProgress int    `json:"InProgress"`
+	Done       int    `json:"Done"`
+}
+
+// TaskInclude is a resource related to tasks that responses embed in them on request.
+type TaskInclude string
+
+const (
+	IncludeComments TaskInclude = "comments"
+	IncludeAssignee TaskInclude = "assignee"
+)
+
+var TaskIncludes = []TaskInclude{IncludeComments, IncludeAssignee}
+
+// ParseTaskIncludes reads a list of includes such as "comments,assignee".
+func ParseTaskIncludes(value string) ([]TaskInclude, error) {
+	var includes []TaskInclude
+	for _, name := range strings.Split(value, ",") {
+		include := TaskInclude(name)
+		if !slices.Contains(TaskIncludes, include) {
+			return nil, fmt.Errorf("can't include %q in tasks", name)
+		}
+
+		if !slices.Contains(includes, include) {
+			includes = append(includes, include)
+		}
+	}
+	return includes, nil
+}
+
+// TaskRelations holds the resources related to a task, only the included ones are set.
+type TaskRelations struct {
+	Comments []Comment `json:"Comments"`
+	Assignee *User     `json:"Assignee"`
+}
+
+// SearchResult is a task found by a full-text search, along with excerpts of where it matched.
+type SearchResult struct {
+	Task     Task            `json:"Task"`
+	Score    float64         `json:"Score"`
+	Snippets []SearchSnippet `json:"Snippets"`
+}
+
+// SearchSnippet is an excerpt of the description or of a comment of a task. Text is HTML,
+// with the matching words wrapped in <mark>.
+type SearchSnippet struct {
+	Field     string `json:"Field"`
+	CommentId int    `json:"CommentId,omitempty"`
+	Text      string `json:"Text"`
+}
+
+// EstimateTotal sums the estimates of a group of tasks, only the field the tasks were
+// grouped by is set.
+type EstimateTotal struct {
+	Status            *TaskStatus `json:"Status,omitempty"`
+	Tag               string      `json:"Tag,omitempty"`
+	Tasks             int         `json:"Tasks"`
+	StoryPoints       int         `json:"StoryPoints"`
+	OriginalEstimate  Duration    `json:"OriginalEstimate"`
+	RemainingEstimate Duration    `json:"RemainingEstimate"`
 }
@@ -1,8 +1,11 @@
 package model
 
 import (
+	"encoding/json"
 	"fmt"
+	"slices"
 	"testing"
+	"time"
 )
 
 func TestTaskStatus(t *testing.T) {
@@ -28,3 +31,160 @@ func TestTaskStatus(t *testing.T) {
 		})
 	}
 }
+
+func TestTaskStatusName(t *testing.T) {
+
+	var testTable = []struct {
+		status   TaskStatus
+		expected string
+	}{
+		{0, "todo"},
+		{1, "in_progress"},
+		{2, "done"},
+		{3, "Unknown(3)"},
+		{-1, "Unknown(-1)"},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%d), Expect: %s", testData.status, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			answer := testData.status.Name()
+			if answer != testData.expected {
+				t.Errorf("with input (%d) got %s, but expected %s", testData.status, answer, testData.expected)
+			}
+		})
+	}
+}
+
+func TestDateTimeUnmarshalJSON(t *testing.T) {
+
+	var testTable = []struct {
+		input    string
+		expected time.Time
+	}{
+		{`"2026-01-07T09:30:00Z"`, time.Date(2026, 1, 7, 9, 30, 0, 0, time.UTC)},
+		{`"2026-01-07T09:30:00+02:00"`, time.Date(2026, 1, 7, 7, 30, 0, 0, time.UTC)},
+		{`"2026-01-07 09:30:00"`, time.Date(2026, 1, 7, 9, 30, 0, 0, time.Local)},
+	}
+
+	for _, testData := range testTable {
+
+		t.Run(testData.input, func(t *testing.T) {
+			var answer DateTime
+			if err := json.Unmarshal([]byte(testData.input), &answer); err != nil {
+				t.Fatalf("with input %s got error %s", testData.input, err)
+			}
+
+			if !time.Time(answer).Equal(testData.expected) || time.Time(answer).Location() != time.UTC {
+				t.Errorf("with input %s got %s, but expected %s in UTC", testData.input, time.Time(answer), testData.expected)
+			}
+		})
+	}
+}
+
+func TestDateTimeMarshalJSON(t *testing.T) {
+	b, err := json.Marshal(DateTime(time.Date(2026, 1, 7, 9, 30, 0, 0, time.UTC)))
+	if err != nil {
+		t.Fatalf("failed to marshal DateTime: %s", err)
+	}
+
+	if string(b) != `"2026-01-07T09:30:00Z"` {
+		t.Errorf("got %s, but expected \"2026-01-07T09:30:00Z\"", b)
+	}
+}
+
+func TestTaskStatusUnmarshalJSON(t *testing.T) {
+
+	var testTable = []struct {
+		input    string
+		expected TaskStatus
+		valid    bool
+	}{
+		{`"todo"`, TODO, true},
+		{`"in_progress"`, InProgress, true},
+		{`"done"`, Done, true},
+		{`1`, InProgress, true},
+		{`7`, 0, false},
+		{`-1`, 0, false},
+		{`"finished"`, 0, false},
+	}
+
+	for _, testData := range testTable {
+
+		t.Run(testData.input, func(t *testing.T) {
+			var answer TaskStatus
+			err := json.Unmarshal([]byte(testData.input), &answer)
+			if testData.valid != (err == nil) {
+				t.Fatalf("with input %s got error %v", testData.input, err)
+			}
+			if answer != testData.expected {
+				t.Errorf("with input %s got %d, but expected %d", testData.input, answer, testData.expected)
+			}
+		})
+	}
+}
+
+func TestParseTaskSort(t *testing.T) {
+
+	var testTable = []struct {
+		input    string
+		expected string
+		err      bool
+	}{
+		{"dueAt", "dueAt", false},
+		{"-cf.severity", "-cf.severity", false},
+		{"status,-dueAt,id", "status,-dueAt,id", false},
+		{"owner", "", true},
+		{"status,", "", true},
+		{"status,-status", "", true},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%s), Expect: %s", testData.input, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			sort, err := ParseTaskSort(testData.input)
+			if (err != nil) != testData.err {
+				t.Fatalf("with input (%s) got error %v, but expected error %t", testData.input, err, testData.err)
+			}
+
+			if answer := sort.String(); answer != testData.expected {
+				t.Errorf("with input (%s) got %s, but expected %s", testData.input, answer, testData.expected)
+			}
+		})
+	}
+}
+
+func TestParseTaskIncludes(t *testing.T) {
+
+	var testTable = []struct {
+		input    string
+		expected []TaskInclude
+		err      bool
+	}{
+		{"comments", []TaskInclude{IncludeComments}, false},
+		{"assignee,comments,assignee", []TaskInclude{IncludeAssignee, IncludeComments}, false},
+		{"children", nil, true},
+		{"comments,watchers", nil, true},
+		{"Comments", nil, true},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%s), Expect: %v", testData.input, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			includes, err := ParseTaskIncludes(testData.input)
+			if (err != nil) != testData.err {
+				t.Fatalf("with input (%s) got error %v, but expected error %t", testData.input, err, testData.err)
+			}
+
+			if !slices.Equal(includes, testData.expected) {
+				t.Errorf("with input (%s) got %v, but expected %v", testData.input, includes, testData.expected)
+			}
+		})
+	}
+}
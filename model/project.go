@@ -0,0 +1,86 @@
+package model
+
+// Project groups tasks, which get keys made of the project Key and a number such as OPS-42.
+type Project struct {
+	Id          int    `json:"Id"`
+	Key         string `json:"Key"`
+	Name        string `json:"Name"`
+	Description string `json:"Description,omitempty"`
+	// DefaultAssigneeId is assigned to tasks created in the project without an assignee.
+	DefaultAssigneeId int `json:"DefaultAssigneeId,omitempty"`
+	// Workflow lists the status changes allowed for tasks of the project, an empty
+	// workflow allows any change.
+	Workflow []Transition `json:"Workflow,omitempty"`
+	// Fields defines the custom fields of the tasks of the project.
+	Fields []FieldDefinition `json:"Fields,omitempty"`
+	// TaskSequence is the number given to the last task created in or moved to the project.
+	TaskSequence int      `json:"TaskSequence"`
+	CreatedAt    DateTime `json:"CreatedAt"`
+	UpdatedAt    DateTime `json:"UpdatedAt"`
+}
+
+type Transition struct {
+	From TaskStatus `json:"From"`
+	To   TaskStatus `json:"To"`
+}
+
+// Allows reports whether the workflow of p lets a task change from status from to status to.
+func (p Project) Allows(from TaskStatus, to TaskStatus) bool {
+	if len(p.Workflow) == 0 || from == to {
+		return true
+	}
+
+	for _, transition := range p.Workflow {
+		if transition.From == from && transition.To == to {
+			return true
+		}
+	}
+	return false
+}
+
+type CreateProject struct {
+	Key               string            `json:"key"`
+	Name              string            `json:"name"`
+	Description       string            `json:"description"`
+	DefaultAssigneeId int               `json:"defaultAssigneeId"`
+	Workflow          []Transition      `json:"workflow"`
+	Fields            []FieldDefinition `json:"fields"`
+}
+
+// UpdateProject holds the settings to change on a project, nil fields are left untouched.
+// The key of a project can't be changed.
+type UpdateProject struct {
+	Name              *string            `json:"name"`
+	Description       *string            `json:"description"`
+	DefaultAssigneeId *int               `json:"defaultAssigneeId"`
+	Workflow          *[]Transition      `json:"workflow"`
+	Fields            *[]FieldDefinition `json:"fields"`
+}
+
+// Apply copies every non nil field of u into project.
+func (u UpdateProject) Apply(project *Project) {
+	if u.Name != nil {
+		project.Name = *u.Name
+	}
+
+	if u.Description != nil {
+		project.Description = *u.Description
+	}
+
+	if u.DefaultAssigneeId != nil {
+		project.DefaultAssigneeId = *u.DefaultAssigneeId
+	}
+
+	if u.Workflow != nil {
+		project.Workflow = *u.Workflow
+	}
+
+	if u.Fields != nil {
+		project.Fields = *u.Fields
+	}
+}
+
+// MoveTask is the request to move a task to another project.
+type MoveTask struct {
+	Project string `json:"project"`
+}
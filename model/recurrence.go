@@ -0,0 +1,138 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "daily"
+	Weekly  Frequency = "weekly"
+	Monthly Frequency = "monthly"
+	// AfterCompletion schedules the next occurrence Interval days after a task is done.
+	AfterCompletion Frequency = "after_completion"
+)
+
+// Weekday is a time.Weekday written as the two letter codes used by RRULE (MO, TU, ...).
+type Weekday time.Weekday
+
+var weekdayCodes = [...]string{"SU", "MO", "TU", "WE", "TH", "FR", "SA"}
+
+func (d Weekday) MarshalText() ([]byte, error) {
+	if d < 0 || int(d) >= len(weekdayCodes) {
+		return nil, fmt.Errorf("invalid weekday %d", d)
+	}
+	return []byte(weekdayCodes[d]), nil
+}
+
+func (d *Weekday) UnmarshalText(b []byte) error {
+	for i, code := range weekdayCodes {
+		if strings.EqualFold(code, string(b)) {
+			*d = Weekday(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid weekday %q", b)
+}
+
+// Recurrence describes when the next occurrence of a task is due.
+type Recurrence struct {
+	Frequency Frequency `json:"Frequency"`
+	// Interval is the number of days, weeks or months between occurrences, 0 means 1.
+	Interval int `json:"Interval,omitempty"`
+	// Weekdays are the days of the week a Weekly task is due on.
+	Weekdays []Weekday `json:"Weekdays,omitempty"`
+	// MonthDay is the day of the month a Monthly task is due on, it is moved to the last
+	// day of shorter months. 0 keeps the day of the current due date, which the next
+	// occurrences are given as their MonthDay, see Anchor.
+	MonthDay int `json:"MonthDay,omitempty"`
+}
+
+func (r Recurrence) Validate() error {
+	switch r.Frequency {
+	case Daily, Weekly, Monthly, AfterCompletion:
+	default:
+		return fmt.Errorf("invalid recurrence frequency %q", r.Frequency)
+	}
+
+	if r.Interval < 0 {
+		return fmt.Errorf("invalid recurrence interval %d", r.Interval)
+	}
+
+	if len(r.Weekdays) > 0 && r.Frequency != Weekly {
+		return fmt.Errorf("weekdays are only allowed for %s recurrences", Weekly)
+	}
+
+	if r.MonthDay < 0 || r.MonthDay > 31 || (r.MonthDay != 0 && r.Frequency != Monthly) {
+		return fmt.Errorf("invalid recurrence month day %d", r.MonthDay)
+	}
+	return nil
+}
+
+// Anchor returns r with the day of the month of a Monthly recurrence without MonthDay set
+// to the day of due, so the day is kept after a shorter month moved it: Jan 31 is followed
+// by Feb 28 and then Mar 31.
+func (r Recurrence) Anchor(due time.Time) Recurrence {
+	if r.Frequency == Monthly && r.MonthDay == 0 {
+		r.MonthDay = due.Day()
+	}
+	return r
+}
+
+// Next returns the due date of the occurrence following the one due at due and
+// completed at completed.
+func (r Recurrence) Next(due time.Time, completed time.Time) time.Time {
+	interval := max(r.Interval, 1)
+
+	switch r.Frequency {
+	case Weekly:
+		return r.nextWeekly(due, interval)
+	case Monthly:
+		return r.nextMonthly(due, interval)
+	case AfterCompletion:
+		return completed.AddDate(0, 0, interval)
+	default:
+		return due.AddDate(0, 0, interval)
+	}
+}
+
+func (r Recurrence) nextWeekly(due time.Time, interval int) time.Time {
+	if len(r.Weekdays) == 0 {
+		return due.AddDate(0, 0, 7*interval)
+	}
+
+	// Days are counted from monday, as RRULE weeks start on monday by default.
+	offset := func(d time.Weekday) int { return (int(d) + 6) % 7 }
+
+	dueOffset := offset(due.Weekday())
+	first, next := 7, 7
+	for _, day := range r.Weekdays {
+		dayOffset := offset(time.Weekday(day))
+		first = min(first, dayOffset)
+		if dayOffset > dueOffset {
+			next = min(next, dayOffset)
+		}
+	}
+
+	if next < 7 {
+		return due.AddDate(0, 0, next-dueOffset)
+	}
+
+	monday := due.AddDate(0, 0, -dueOffset)
+	return monday.AddDate(0, 0, 7*interval+first)
+}
+
+func (r Recurrence) nextMonthly(due time.Time, interval int) time.Time {
+	day := r.MonthDay
+	if day == 0 {
+		day = due.Day()
+	}
+
+	firstOfMonth := time.Date(due.Year(), due.Month()+time.Month(interval), 1,
+		due.Hour(), due.Minute(), due.Second(), due.Nanosecond(), due.Location())
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	return firstOfMonth.AddDate(0, 0, min(day, lastDay)-1)
+}
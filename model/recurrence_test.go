@@ -0,0 +1,77 @@
+package model
+
+import (
+	"fmt"
+	"testing"
+	"time"
+)
+
+func TestRecurrenceNext(t *testing.T) {
+
+	// 2026-01-07 is a wednesday.
+	due := time.Date(2026, 1, 7, 9, 0, 0, 0, time.UTC)
+	completed := time.Date(2026, 1, 9, 18, 30, 0, 0, time.UTC)
+
+	var testTable = []struct {
+		recurrence Recurrence
+		expected   time.Time
+	}{
+		{Recurrence{Frequency: Daily}, time.Date(2026, 1, 8, 9, 0, 0, 0, time.UTC)},
+		{Recurrence{Frequency: Daily, Interval: 3}, time.Date(2026, 1, 10, 9, 0, 0, 0, time.UTC)},
+		{Recurrence{Frequency: Weekly}, time.Date(2026, 1, 14, 9, 0, 0, 0, time.UTC)},
+		{Recurrence{Frequency: Weekly, Weekdays: []Weekday{1, 5}}, time.Date(2026, 1, 9, 9, 0, 0, 0, time.UTC)},
+		{Recurrence{Frequency: Weekly, Weekdays: []Weekday{1, 3}}, time.Date(2026, 1, 12, 9, 0, 0, 0, time.UTC)},
+		{Recurrence{Frequency: Weekly, Interval: 2, Weekdays: []Weekday{0, 2}}, time.Date(2026, 1, 11, 9, 0, 0, 0, time.UTC)},
+		{Recurrence{Frequency: Weekly, Interval: 2, Weekdays: []Weekday{2}}, time.Date(2026, 1, 20, 9, 0, 0, 0, time.UTC)},
+		{Recurrence{Frequency: Monthly}, time.Date(2026, 2, 7, 9, 0, 0, 0, time.UTC)},
+		{Recurrence{Frequency: Monthly, MonthDay: 31}, time.Date(2026, 2, 28, 9, 0, 0, 0, time.UTC)},
+		{Recurrence{Frequency: Monthly, Interval: 2, MonthDay: 31}, time.Date(2026, 3, 31, 9, 0, 0, 0, time.UTC)},
+		{Recurrence{Frequency: AfterCompletion, Interval: 5}, time.Date(2026, 1, 14, 18, 30, 0, 0, time.UTC)},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%+v), Expect: %s", testData.recurrence, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			answer := testData.recurrence.Next(due, completed)
+			if !answer.Equal(testData.expected) {
+				t.Errorf("with input (%+v) got %s, but expected %s", testData.recurrence, answer, testData.expected)
+			}
+		})
+	}
+}
+
+func TestRecurrenceAnchor(t *testing.T) {
+
+	var testTable = []struct {
+		recurrence Recurrence
+		due        time.Time
+		expected   []time.Time
+	}{
+		{Recurrence{Frequency: Monthly}, time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC),
+			[]time.Time{time.Date(2026, 2, 28, 9, 0, 0, 0, time.UTC), time.Date(2026, 3, 31, 9, 0, 0, 0, time.UTC), time.Date(2026, 4, 30, 9, 0, 0, 0, time.UTC)}},
+		{Recurrence{Frequency: Monthly, MonthDay: 15}, time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC),
+			[]time.Time{time.Date(2026, 2, 15, 9, 0, 0, 0, time.UTC), time.Date(2026, 3, 15, 9, 0, 0, 0, time.UTC), time.Date(2026, 4, 15, 9, 0, 0, 0, time.UTC)}},
+		{Recurrence{Frequency: Daily}, time.Date(2026, 1, 31, 9, 0, 0, 0, time.UTC),
+			[]time.Time{time.Date(2026, 2, 1, 9, 0, 0, 0, time.UTC), time.Date(2026, 2, 2, 9, 0, 0, 0, time.UTC), time.Date(2026, 2, 3, 9, 0, 0, 0, time.UTC)}},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%+v, %s), Expect: %v", testData.recurrence, testData.due, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			recurrence := testData.recurrence
+			due := testData.due
+			for i, expected := range testData.expected {
+				// each occurrence anchors the recurrence it passes on, as addNextOccurrence does
+				recurrence = recurrence.Anchor(due)
+				due = recurrence.Next(due, due)
+				if !due.Equal(expected) {
+					t.Errorf("with input (%+v, %s) got %s for occurrence %d, but expected %s", testData.recurrence, testData.due, due, i+1, expected)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,80 @@
+package model
+
+// Sprint is a time box tasks are planned into.
+type Sprint struct {
+	Id        int      `json:"Id"`
+	Name      string   `json:"Name"`
+	ProjectId int      `json:"ProjectId,omitempty"`
+	Goal      string   `json:"Goal,omitempty"`
+	StartAt   DateTime `json:"StartAt"`
+	EndAt     DateTime `json:"EndAt"`
+	CreatedAt DateTime `json:"CreatedAt"`
+	UpdatedAt DateTime `json:"UpdatedAt"`
+}
+
+type CreateSprint struct {
+	Name string `json:"name"`
+	// Project is the key of the project the sprint belongs to, if any.
+	Project string   `json:"project"`
+	Goal    string   `json:"goal"`
+	StartAt DateTime `json:"startAt"`
+	EndAt   DateTime `json:"endAt"`
+}
+
+// UpdateSprint holds the fields to change on a sprint, nil fields are left untouched.
+type UpdateSprint struct {
+	Name    *string   `json:"name"`
+	Goal    *string   `json:"goal"`
+	StartAt *DateTime `json:"startAt"`
+	EndAt   *DateTime `json:"endAt"`
+}
+
+// Apply copies every non nil field of u into sprint.
+func (u UpdateSprint) Apply(sprint *Sprint) {
+	if u.Name != nil {
+		sprint.Name = *u.Name
+	}
+
+	if u.Goal != nil {
+		sprint.Goal = *u.Goal
+	}
+
+	if u.StartAt != nil {
+		sprint.StartAt = *u.StartAt
+	}
+
+	if u.EndAt != nil {
+		sprint.EndAt = *u.EndAt
+	}
+}
+
+// SprintScope is the work currently planned in a sprint and how much of it is done.
+type SprintScope struct {
+	Sprint Sprint `json:"Sprint"`
+	Tasks  []Task `json:"Tasks"`
+	// CommittedTasks is the number of tasks in the sprint when it started.
+	CommittedTasks    int     `json:"CommittedTasks"`
+	DoneTasks         int     `json:"DoneTasks"`
+	StoryPoints       int     `json:"StoryPoints"`
+	DoneStoryPoints   int     `json:"DoneStoryPoints"`
+	Completion        float64 `json:"Completion"`
+	PointsCompletion  float64 `json:"PointsCompletion"`
+	AddedAfterStart   int     `json:"AddedAfterStart"`
+	RemovedAfterStart int     `json:"RemovedAfterStart"`
+}
+
+type ScopeChangeType string
+
+const (
+	ScopeAdded   ScopeChangeType = "added"
+	ScopeRemoved ScopeChangeType = "removed"
+)
+
+// SprintScopeChange is a task added to or removed from a sprint after it started.
+type SprintScopeChange struct {
+	TaskId  int             `json:"TaskId"`
+	Key     string          `json:"Key,omitempty"`
+	Type    ScopeChangeType `json:"Type"`
+	ActorId int             `json:"ActorId,omitempty"`
+	At      DateTime        `json:"At"`
+}
@@ -0,0 +1,21 @@
+package model
+
+type User struct {
+	Id        int      `json:"Id"`
+	Name      string   `json:"Name"`
+	Email     string   `json:"Email"`
+	TokenHash string   `json:"TokenHash,omitempty"`
+	CreatedAt DateTime `json:"CreatedAt"`
+}
+
+// UserToken is a user along with the API token it authenticates with, which is only known
+// when the user is created.
+type UserToken struct {
+	User
+	Token string `json:"Token"`
+}
+
+type CreateUser struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
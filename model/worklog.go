@@ -0,0 +1,73 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Duration is a time.Duration written in JSON as a string such as "1h30m0s". Numbers
+// are also accepted when decoding and read as seconds.
+type Duration time.Duration
+
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+func (d *Duration) UnmarshalJSON(b []byte) error {
+	var seconds float64
+	if err := json.Unmarshal(b, &seconds); err == nil {
+		*d = Duration(seconds * float64(time.Second))
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(b, &text); err != nil {
+		return fmt.Errorf("invalid duration %s", b)
+	}
+
+	duration, err := time.ParseDuration(text)
+	if err != nil {
+		return err
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// WorkLog is time spent by a user on a task. Entries created by a timer are Running
+// until the timer is stopped.
+type WorkLog struct {
+	Id        int      `json:"Id"`
+	TaskId    int      `json:"TaskId"`
+	UserId    int      `json:"UserId"`
+	StartedAt DateTime `json:"StartedAt"`
+	Duration  Duration `json:"Duration"`
+	Note      string   `json:"Note,omitempty"`
+	Running   bool     `json:"Running,omitempty"`
+	CreatedAt DateTime `json:"CreatedAt"`
+}
+
+type CreateWorkLog struct {
+	Duration  Duration  `json:"duration"`
+	StartedAt *DateTime `json:"startedAt"`
+	Note      string    `json:"note"`
+}
+
+// WorkLogFilter narrows the work logs taken into account, zero values match every entry.
+type WorkLogFilter struct {
+	TaskId int
+	UserId int
+	From   time.Time
+	To     time.Time
+	// Location is the time zone work logs are grouped into dates in, UTC when nil.
+	Location *time.Location
+}
+
+// WorkLogTotal is the time logged for one group of work logs, only the field the
+// entries were grouped by is set.
+type WorkLogTotal struct {
+	TaskId   int      `json:"TaskId,omitempty"`
+	UserId   int      `json:"UserId,omitempty"`
+	Date     string   `json:"Date,omitempty"`
+	Duration Duration `json:"Duration"`
+}
@@ -0,0 +1,98 @@
+// Package query parses the query language of task listings, such as
+//
+//	status:todo AND (tag:backend OR description~"flaky") AND created>2026-01-01
+//
+// into an Expr. Terms are joined with AND, OR and NOT, or a leading -, and grouped with
+// parentheses, adjacent terms are joined with AND. The package knows the syntax only,
+// which fields exist and what their values mean is up to the caller.
+package query
+
+import (
+	"strings"
+)
+
+// Operator compares a field with a value.
+type Operator string
+
+const (
+	Equal          Operator = ":"
+	NotEqual       Operator = "!="
+	Contains       Operator = "~"
+	Greater        Operator = ">"
+	GreaterOrEqual Operator = ">="
+	Less           Operator = "<"
+	LessOrEqual    Operator = "<="
+)
+
+// Expr is a node of a parsed query. String writes the node back as a query, with
+// parentheses around every AND and OR.
+type Expr interface {
+	String() string
+	expr()
+}
+
+type And struct {
+	Left  Expr
+	Right Expr
+}
+
+type Or struct {
+	Left  Expr
+	Right Expr
+}
+
+type Not struct {
+	Expr Expr
+}
+
+// Comparison compares the field Field with Value. Pos is the byte offset of the
+// comparison in the query, for errors about it.
+type Comparison struct {
+	Field string
+	Op    Operator
+	Value string
+	Pos   int
+}
+
+// Text is a value written without a field.
+type Text struct {
+	Value string
+	Pos   int
+}
+
+func (*And) expr()        {}
+func (*Or) expr()         {}
+func (*Not) expr()        {}
+func (*Comparison) expr() {}
+func (*Text) expr()       {}
+
+func (e *And) String() string {
+	return "(" + e.Left.String() + " AND " + e.Right.String() + ")"
+}
+
+func (e *Or) String() string {
+	return "(" + e.Left.String() + " OR " + e.Right.String() + ")"
+}
+
+func (e *Not) String() string {
+	return "NOT " + e.Expr.String()
+}
+
+func (e *Comparison) String() string {
+	return e.Field + string(e.Op) + quote(e.Value)
+}
+
+func (e *Text) String() string {
+	return quote(e.Value)
+}
+
+var quoteReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+// quote writes value as a word when it reads back as one and as a string otherwise.
+func quote(value string) string {
+	tokens, err := lex(value)
+	if err == nil && len(tokens) == 2 && tokens[0].kind == tokenWord && tokens[0].text == value {
+		return value
+	}
+	return `"` + quoteReplacer.Replace(value) + `"`
+}
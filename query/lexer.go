@@ -0,0 +1,153 @@
+package query
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOperator
+	tokenLeftParen
+	tokenRightParen
+	tokenAnd
+	tokenOr
+	tokenNot
+)
+
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+// describe names t in error messages.
+func (t token) describe() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of query"
+	case tokenString:
+		return fmt.Sprintf("string %q", t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// SyntaxError is a query that can't be parsed. Pos is the byte offset in Query where
+// the problem was found.
+type SyntaxError struct {
+	Query string
+	Pos   int
+	Msg   string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("column %d: %s", column(e.Query, e.Pos), e.Msg)
+}
+
+// column returns the column of the byte offset pos in input, counting from 1.
+func column(input string, pos int) int {
+	return utf8.RuneCountInString(input[:pos]) + 1
+}
+
+// operatorChars are the characters operators are made of, they end field names.
+const operatorChars = ":~=!<>"
+
+var keywords = map[string]tokenKind{
+	"AND": tokenAnd,
+	"OR":  tokenOr,
+	"NOT": tokenNot,
+}
+
+// lex splits input into tokens, the last one being tokenEOF. The word following an
+// operator is a value, which may hold operator characters, as in due>2026-01-01T10:00:00Z.
+func lex(input string) ([]token, error) {
+	var tokens []token
+	previous := tokenEOF
+	for i := 0; i < len(input); {
+		r, size := utf8.DecodeRuneInString(input[i:])
+		start := i
+
+		var t token
+		switch {
+		case unicode.IsSpace(r):
+			i += size
+			continue
+		case r == '(':
+			t, i = token{kind: tokenLeftParen, text: "(", pos: start}, i+1
+		case r == ')':
+			t, i = token{kind: tokenRightParen, text: ")", pos: start}, i+1
+		case r == '"':
+			value, end, err := lexString(input, start)
+			if err != nil {
+				return nil, err
+			}
+			t, i = token{kind: tokenString, text: value, pos: start}, end
+		case strings.ContainsRune(operatorChars, r) && previous != tokenOperator:
+			op, err := lexOperator(input, start)
+			if err != nil {
+				return nil, err
+			}
+			t, i = token{kind: tokenOperator, text: op, pos: start}, start+len(op)
+		case r == '-' && previous != tokenOperator:
+			t, i = token{kind: tokenNot, text: "-", pos: start}, i+1
+		default:
+			stop := " \t\r\n()\"" + operatorChars
+			if previous == tokenOperator {
+				stop = " \t\r\n()\""
+			}
+			end := strings.IndexAny(input[start:], stop)
+			if end == -1 {
+				end = len(input) - start
+			}
+
+			word := input[start : start+end]
+			t, i = token{kind: tokenWord, text: word, pos: start}, start+end
+			if kind, ok := keywords[word]; ok && previous != tokenOperator {
+				t.kind = kind
+			}
+		}
+
+		tokens = append(tokens, t)
+		previous = t.kind
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(input)}), nil
+}
+
+// lexString reads the string starting with the quote at input[start], returning its
+// value and the offset following the closing quote. \" and \\ escape quotes and
+// backslashes.
+func lexString(input string, start int) (string, int, error) {
+	var value strings.Builder
+	for i := start + 1; i < len(input); i++ {
+		switch input[i] {
+		case '"':
+			return value.String(), i + 1, nil
+		case '\\':
+			if i+1 == len(input) || (input[i+1] != '"' && input[i+1] != '\\') {
+				return "", 0, &SyntaxError{Query: input, Pos: i, Msg: `invalid escape in string, only \" and \\ are allowed`}
+			}
+			i++
+		}
+		value.WriteByte(input[i])
+	}
+	return "", 0, &SyntaxError{Query: input, Pos: start, Msg: "unterminated string, missing the closing \""}
+}
+
+func lexOperator(input string, start int) (string, error) {
+	for _, op := range []Operator{GreaterOrEqual, LessOrEqual, NotEqual, Equal, Contains, Greater, Less} {
+		if strings.HasPrefix(input[start:], string(op)) {
+			return string(op), nil
+		}
+	}
+
+	if input[start] == '=' {
+		return "=", nil
+	}
+	return "", &SyntaxError{Query: input, Pos: start, Msg: `unexpected "!", did you mean "!="`}
+}
@@ -0,0 +1,162 @@
+package query
+
+import (
+	"fmt"
+)
+
+// Parse parses input into an Expr. AND binds tighter than OR and NOT tighter than both.
+// It returns a *SyntaxError telling where input went wrong when it isn't a valid query.
+func Parse(input string) (Expr, error) {
+	tokens, err := lex(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := parser{input: input, tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, p.errorAt(p.peek(), "query is empty")
+	}
+
+	expr, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+
+	if next := p.peek(); next.kind != tokenEOF {
+		if next.kind == tokenRightParen {
+			return nil, p.errorAt(next, `unexpected ")" without a matching "("`)
+		}
+		return nil, p.errorAt(next, fmt.Sprintf("unexpected %s", next.describe()))
+	}
+	return expr, nil
+}
+
+type parser struct {
+	input  string
+	tokens []token
+	pos    int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+func (p *parser) errorAt(t token, msg string) error {
+	return &SyntaxError{Query: p.input, Pos: t.pos, Msg: msg}
+}
+
+// parseOr parses terms joined with OR.
+func (p *parser) parseOr() (Expr, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = &Or{Left: left, Right: right}
+	}
+	return left, nil
+}
+
+// parseAnd parses terms joined with AND or just written one after the other.
+func (p *parser) parseAnd() (Expr, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		switch p.peek().kind {
+		case tokenAnd:
+			p.next()
+		case tokenWord, tokenString, tokenLeftParen, tokenNot:
+		default:
+			return left, nil
+		}
+
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = &And{Left: left, Right: right}
+	}
+}
+
+func (p *parser) parseUnary() (Expr, error) {
+	if p.peek().kind != tokenNot {
+		return p.parsePrimary()
+	}
+
+	p.next()
+	expr, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return &Not{Expr: expr}, nil
+}
+
+func (p *parser) parsePrimary() (Expr, error) {
+	index := p.pos
+	t := p.next()
+	switch t.kind {
+	case tokenLeftParen:
+		expr, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+
+		if closing := p.next(); closing.kind != tokenRightParen {
+			return nil, p.errorAt(closing, fmt.Sprintf(`expected ")" to close the "(" at column %d, got %s`, column(p.input, t.pos), closing.describe()))
+		}
+		return expr, nil
+
+	case tokenWord:
+		if p.peek().kind != tokenOperator {
+			return &Text{Value: t.text, Pos: t.pos}, nil
+		}
+		return p.parseComparison(t)
+
+	case tokenString:
+		if op := p.peek(); op.kind == tokenOperator {
+			return nil, p.errorAt(t, fmt.Sprintf("expected a field name before %q, field names can't be quoted", op.text))
+		}
+		return &Text{Value: t.text, Pos: t.pos}, nil
+
+	case tokenOperator:
+		return nil, p.errorAt(t, fmt.Sprintf("expected a field name before %q", t.text))
+	}
+
+	if index > 0 {
+		if previous := p.tokens[index-1]; previous.kind == tokenAnd || previous.kind == tokenOr || previous.kind == tokenNot {
+			return nil, p.errorAt(t, fmt.Sprintf("expected a term after %s, got %s", previous.text, t.describe()))
+		}
+	}
+	return nil, p.errorAt(t, fmt.Sprintf("expected a term, got %s", t.describe()))
+}
+
+func (p *parser) parseComparison(field token) (Expr, error) {
+	op := p.next()
+	value := p.next()
+	if value.kind != tokenWord && value.kind != tokenString {
+		return nil, p.errorAt(value, fmt.Sprintf("expected a value after %q, got %s", field.text+op.text, value.describe()))
+	}
+
+	operator := Operator(op.text)
+	if op.text == "=" {
+		operator = Equal
+	}
+	return &Comparison{Field: field.text, Op: operator, Value: value.text, Pos: field.pos}, nil
+}
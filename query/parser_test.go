@@ -0,0 +1,89 @@
+package query
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+
+	var testTable = []struct {
+		input    string
+		expected string
+	}{
+		{`status:todo`, `status:todo`},
+		{`status=todo`, `status:todo`},
+		{`status:todo AND tag:backend`, `(status:todo AND tag:backend)`},
+		{`status:todo tag:backend`, `(status:todo AND tag:backend)`},
+		{`a:1 OR b:2 AND c:3`, `(a:1 OR (b:2 AND c:3))`},
+		{`(a:1 OR b:2) AND c:3`, `((a:1 OR b:2) AND c:3)`},
+		{`NOT a:1 OR b:2`, `(NOT a:1 OR b:2)`},
+		{`-tag:backend`, `NOT tag:backend`},
+		{`NOT (a:1 OR b:2)`, `NOT (a:1 OR b:2)`},
+		{`description~"flaky test"`, `description~"flaky test"`},
+		{`description~"say \"hi\""`, `description~"say \"hi\""`},
+		{`created>2026-01-01`, `created>2026-01-01`},
+		{`created>=2026-01-01 created<=2026-02-01`, `(created>=2026-01-01 AND created<=2026-02-01)`},
+		{`due<2026-01-01T10:00:00Z`, `due<"2026-01-01T10:00:00Z"`},
+		{`status!=done`, `status!=done`},
+		{`storyPoints>-1`, `storyPoints>"-1"`},
+		{`cf.severity:high`, `cf.severity:high`},
+		{`description:AND`, `description:"AND"`},
+		{`flaky`, `flaky`},
+		{`"flaky test" status:todo`, `("flaky test" AND status:todo)`},
+		{`status:todo AND (tag:backend OR description~"flaky") AND created>2026-01-01`, `((status:todo AND (tag:backend OR description~flaky)) AND created>2026-01-01)`},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%s), Expect: %s", testData.input, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			expr, err := Parse(testData.input)
+			if err != nil {
+				t.Fatalf("expected Parse call to return no errors, got \"%s\"", err)
+			}
+
+			if answer := expr.String(); answer != testData.expected {
+				t.Errorf("with input (%s) got %s, but expected %s", testData.input, answer, testData.expected)
+			}
+		})
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+
+	var testTable = []struct {
+		input    string
+		expected string
+	}{
+		{``, `column 1: query is empty`},
+		{`status:`, `column 8: expected a value after "status:", got end of query`},
+		{`status:todo AND`, `column 16: expected a term after AND, got end of query`},
+		{`status:todo OR )`, `column 16: expected a term after OR, got ")"`},
+		{`(status:todo`, `column 13: expected ")" to close the "(" at column 1, got end of query`},
+		{`status:todo)`, `column 12: unexpected ")" without a matching "("`},
+		{`:todo`, `column 1: expected a field name before ":"`},
+		{`"status":todo`, `column 1: expected a field name before ":", field names can't be quoted`},
+		{`description~"flaky`, `column 13: unterminated string, missing the closing "`},
+		{`description~"a\b"`, `column 15: invalid escape in string, only \" and \\ are allowed`},
+		{`status!todo`, `column 7: unexpected "!", did you mean "!="`},
+		{`é:x AND`, `column 8: expected a term after AND, got end of query`},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%s), Expect: %s", testData.input, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			_, err := Parse(testData.input)
+			if err == nil {
+				t.Fatalf("expected Parse call with input (%s) to return an error", testData.input)
+			}
+
+			if err.Error() != testData.expected {
+				t.Errorf("with input (%s) got error \"%s\", but expected \"%s\"", testData.input, err, testData.expected)
+			}
+		})
+	}
+}
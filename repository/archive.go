@@ -0,0 +1,129 @@
+package repository
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+	"os"
+	"path/filepath"
+	"slices"
+	"sync"
+	"time"
+)
+
+const archiveMonthLayout = "2006-01"
+
+// TaskArchiveDir keeps archived tasks in dir, in one file per month of completion in UTC
+// named like tasks-2026-01.json, so reading recent archives doesn't decode older ones.
+type TaskArchiveDir struct {
+	dir   string
+	mutex sync.Mutex
+}
+
+func NewTaskArchiveDir(dir string) (*TaskArchiveDir, error) {
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create archive dir %s: %w", dir, err)
+	}
+	return &TaskArchiveDir{dir: dir}, nil
+}
+
+// ArchiveTasks adds tasks to the files of the months they were completed in. Tasks
+// already archived are left as they are, so archiving again after a failure is safe.
+func (a *TaskArchiveDir) ArchiveTasks(tasks []model.Task) error {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	byMonth := make(map[string][]model.Task)
+	for _, task := range tasks {
+		month := task.CompletionTime().UTC().Format(archiveMonthLayout)
+		byMonth[month] = append(byMonth[month], task)
+	}
+
+	for month, monthTasks := range byMonth {
+		file, err := newJSONFile[model.Task](a.monthPath(month))
+		if err != nil {
+			return fmt.Errorf("failed to open archive of %s: %w", month, err)
+		}
+
+		err = file.update(func(archived []model.Task) ([]model.Task, error) {
+			for _, task := range monthTasks {
+				if indexOfTask(archived, task.Id) == -1 {
+					archived = append(archived, task)
+				}
+			}
+			return archived, nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to archive tasks of %s: %w", month, err)
+		}
+	}
+	return nil
+}
+
+// GetArchivedTasks returns the tasks completed in the months from from to to, both
+// included and taken in UTC like the file names. A zero from or to leaves that end of
+// the range open.
+func (a *TaskArchiveDir) GetArchivedTasks(from time.Time, to time.Time) ([]model.Task, error) {
+	a.mutex.Lock()
+	defer a.mutex.Unlock()
+
+	months, err := a.months()
+	if err != nil {
+		return nil, err
+	}
+
+	tasks := make([]model.Task, 0)
+	for _, month := range months {
+		if (!from.IsZero() && month < from.UTC().Format(archiveMonthLayout)) || (!to.IsZero() && month > to.UTC().Format(archiveMonthLayout)) {
+			continue
+		}
+
+		file, err := newJSONFile[model.Task](a.monthPath(month))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open archive of %s: %w", month, err)
+		}
+
+		archived, err := file.load()
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, archived...)
+	}
+	return tasks, nil
+}
+
+// LastTaskId returns the highest id of the archived tasks, so new tasks don't reuse it.
+func (a *TaskArchiveDir) LastTaskId() (int, error) {
+	tasks, err := a.GetArchivedTasks(time.Time{}, time.Time{})
+	if err != nil {
+		return 0, err
+	}
+
+	lastId := 0
+	for _, task := range tasks {
+		lastId = max(lastId, task.Id)
+	}
+	return lastId, nil
+}
+
+// months returns the months that have an archive file, oldest first.
+func (a *TaskArchiveDir) months() ([]string, error) {
+	paths, err := filepath.Glob(filepath.Join(a.dir, "tasks-*.json"))
+	if err != nil {
+		return nil, storageError(fmt.Errorf("failed to list archive files: %w", err))
+	}
+
+	months := make([]string, 0, len(paths))
+	for _, path := range paths {
+		var year, month int
+		if _, err := fmt.Sscanf(filepath.Base(path), "tasks-%4d-%2d.json", &year, &month); err != nil {
+			continue
+		}
+		months = append(months, fmt.Sprintf("%04d-%02d", year, month))
+	}
+	slices.Sort(months)
+	return months, nil
+}
+
+func (a *TaskArchiveDir) monthPath(month string) string {
+	return filepath.Join(a.dir, fmt.Sprintf("tasks-%s.json", month))
+}
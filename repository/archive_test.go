@@ -0,0 +1,105 @@
+package repository
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+	"testing"
+	"time"
+)
+
+func Test_ArchiveTasks_ByMonth(t *testing.T) {
+	const dirName = "Test_ArchiveTasks_ByMonth"
+	defer removeTestDir(dirName)
+
+	a, err := NewTaskArchiveDir(dirName)
+	if err != nil {
+		t.Fatalf("failed to create TaskArchiveDir: %s", err)
+	}
+
+	january := model.DateTime(time.Date(2026, 1, 20, 9, 0, 0, 0, time.Local))
+	march := model.DateTime(time.Date(2026, 3, 2, 9, 0, 0, 0, time.Local))
+	tasks := []model.Task{
+		{Id: 1, Status: model.Done, CompletedAt: &january},
+		{Id: 4, Status: model.Done, CompletedAt: &march},
+		{Id: 2, Status: model.Done, UpdatedAt: march},
+	}
+
+	if err = a.ArchiveTasks(tasks); err != nil {
+		t.Fatalf("failed to call ArchiveTasks: \"%v\"", err)
+	}
+
+	// archiving again must not duplicate tasks
+	if err = a.ArchiveTasks(tasks[:1]); err != nil {
+		t.Fatalf("failed to call ArchiveTasks: \"%v\"", err)
+	}
+
+	all, err := a.GetArchivedTasks(time.Time{}, time.Time{})
+	if err != nil {
+		t.Fatalf("failed to call GetArchivedTasks: \"%v\"", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("expected 3 archived tasks, got %d", len(all))
+	}
+
+	fromFebruary, err := a.GetArchivedTasks(time.Date(2026, 2, 1, 0, 0, 0, 0, time.Local), time.Time{})
+	if err != nil {
+		t.Fatalf("failed to call GetArchivedTasks: \"%v\"", err)
+	}
+	if len(fromFebruary) != 2 {
+		t.Errorf("expected 2 tasks archived from february, got %d", len(fromFebruary))
+	}
+
+	lastId, err := a.LastTaskId()
+	if err != nil {
+		t.Fatalf("failed to call LastTaskId: \"%v\"", err)
+	}
+	if lastId != 4 {
+		t.Errorf("expected last archived id 4, got %d", lastId)
+	}
+}
+
+func Test_GetArchivedTasks_OtherZone(t *testing.T) {
+	const dirName = "Test_GetArchivedTasks_OtherZone"
+	defer removeTestDir(dirName)
+
+	a, err := NewTaskArchiveDir(dirName)
+	if err != nil {
+		t.Fatalf("failed to create TaskArchiveDir: %s", err)
+	}
+
+	january := model.DateTime(time.Date(2026, 1, 31, 20, 0, 0, 0, time.UTC))
+	february := model.DateTime(time.Date(2026, 2, 1, 2, 0, 0, 0, time.UTC))
+	tasks := []model.Task{
+		{Id: 1, Status: model.Done, CompletedAt: &january},
+		{Id: 2, Status: model.Done, CompletedAt: &february},
+	}
+	if err = a.ArchiveTasks(tasks); err != nil {
+		t.Fatalf("failed to call ArchiveTasks: \"%v\"", err)
+	}
+
+	var testTable = []struct {
+		from     time.Time
+		to       time.Time
+		expected int
+	}{
+		// february 1st in UTC+9 starts on january 31st at 15:00 UTC
+		{time.Date(2026, 2, 1, 0, 0, 0, 0, time.FixedZone("UTC+9", 9*3600)), time.Time{}, 2},
+		// january 31st at 23:00 in UTC-5 is february 1st at 04:00 UTC
+		{time.Time{}, time.Date(2026, 1, 31, 23, 0, 0, 0, time.FixedZone("UTC-5", -5*3600)), 2},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%s, %s), Expect: %d", testData.from, testData.to, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			archived, err := a.GetArchivedTasks(testData.from, testData.to)
+			if err != nil {
+				t.Fatalf("failed to call GetArchivedTasks: \"%v\"", err)
+			}
+			if len(archived) != testData.expected {
+				t.Errorf("with input (%s, %s) got %d tasks, but expected %d", testData.from, testData.to, len(archived), testData.expected)
+			}
+		})
+	}
+}
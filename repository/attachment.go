@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+	"slices"
+)
+
+type AttachmentRepositoryFile struct {
+	file       *jsonFile[model.Attachment]
+	sequenceId int
+}
+
+func NewAttachmentRepositoryFile(path string) (*AttachmentRepositoryFile, error) {
+	file, err := newJSONFile[model.Attachment](path)
+	if err != nil {
+		return nil, err
+	}
+
+	attachments, err := file.all()
+	if err != nil {
+		return nil, err
+	}
+
+	sequenceId := 0
+	for _, attachment := range attachments {
+		sequenceId = max(sequenceId, attachment.Id)
+	}
+
+	return &AttachmentRepositoryFile{file: file, sequenceId: sequenceId}, nil
+}
+
+func (r *AttachmentRepositoryFile) AddAttachment(attachment model.Attachment) (model.Attachment, error) {
+	err := r.file.update(func(attachments []model.Attachment) ([]model.Attachment, error) {
+		r.sequenceId++
+		attachment.Id = r.sequenceId
+		return append(attachments, attachment), nil
+	})
+	if err != nil {
+		return model.Attachment{}, fmt.Errorf("failed to add attachment: %w", err)
+	}
+	return attachment, nil
+}
+
+func (r *AttachmentRepositoryFile) GetAllAttachments() ([]model.Attachment, error) {
+	attachments, err := r.file.all()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+func (r *AttachmentRepositoryFile) GetAttachments(taskId int) ([]model.Attachment, error) {
+	attachments, err := r.GetAllAttachments()
+	if err != nil {
+		return nil, err
+	}
+
+	taskAttachments := make([]model.Attachment, 0)
+	for _, attachment := range attachments {
+		if attachment.TaskId == taskId {
+			taskAttachments = append(taskAttachments, attachment)
+		}
+	}
+	return taskAttachments, nil
+}
+
+func (r *AttachmentRepositoryFile) GetAttachment(id int) (model.Attachment, error) {
+	attachments, err := r.GetAllAttachments()
+	if err != nil {
+		return model.Attachment{}, err
+	}
+
+	for _, attachment := range attachments {
+		if attachment.Id == id {
+			return attachment, nil
+		}
+	}
+	return model.Attachment{}, fmt.Errorf("attachment with id %d: %w", id, model.ErrNotFound)
+}
+
+func (r *AttachmentRepositoryFile) DeleteAttachment(id int) error {
+	err := r.file.update(func(attachments []model.Attachment) ([]model.Attachment, error) {
+		index := slices.IndexFunc(attachments, func(a model.Attachment) bool { return a.Id == id })
+		if index == -1 {
+			return nil, fmt.Errorf("attachment with id %d: %w", id, model.ErrNotFound)
+		}
+		return slices.Delete(attachments, index, index+1), nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete attachment %d: %w", id, err)
+	}
+	return nil
+}
+
+// DeleteTaskAttachments removes every attachment of the task taskId, their blobs are left
+// for the garbage collector.
+func (r *AttachmentRepositoryFile) DeleteTaskAttachments(taskId int) error {
+	err := r.file.update(func(attachments []model.Attachment) ([]model.Attachment, error) {
+		return slices.DeleteFunc(attachments, func(a model.Attachment) bool { return a.TaskId == taskId }), nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete attachments of task %d: %w", taskId, err)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+package repository
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-task-tracker/model"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+const dirPerm = 0700
+
+// BlobStoreDisk keeps file contents in a directory, each file is named after the
+// SHA-256 hash of its content and nested under a directory named after the first two
+// characters of the hash.
+type BlobStoreDisk struct {
+	dir string
+}
+
+func NewBlobStoreDisk(dir string) (BlobStoreDisk, error) {
+	if err := os.MkdirAll(dir, dirPerm); err != nil {
+		return BlobStoreDisk{}, fmt.Errorf("failed to create blob directory %s: %w", dir, err)
+	}
+	return BlobStoreDisk{dir: dir}, nil
+}
+
+// Put stores the content of reader and returns its hash and size. Content already in the
+// store is not written twice, its modification time is refreshed instead.
+func (b BlobStoreDisk) Put(reader io.Reader) (string, int64, error) {
+	tmp, err := os.CreateTemp(b.dir, "upload-*")
+	if err != nil {
+		return "", 0, storageError(fmt.Errorf("failed to create temporary file: %w", err))
+	}
+	defer os.Remove(tmp.Name())
+
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(tmp, hash), reader)
+	if err != nil {
+		tmp.Close()
+		return "", 0, storageError(fmt.Errorf("failed to write blob: %w", err))
+	}
+
+	if err := tmp.Close(); err != nil {
+		return "", 0, storageError(fmt.Errorf("failed to close temporary file: %w", err))
+	}
+
+	sum := hex.EncodeToString(hash.Sum(nil))
+	path := b.path(sum)
+	if _, err := os.Stat(path); err == nil {
+		now := time.Now()
+		if err := os.Chtimes(path, now, now); err != nil {
+			return "", 0, storageError(fmt.Errorf("failed to touch blob %s: %w", sum, err))
+		}
+		return sum, size, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), dirPerm); err != nil {
+		return "", 0, storageError(fmt.Errorf("failed to create blob directory: %w", err))
+	}
+
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return "", 0, storageError(fmt.Errorf("failed to store blob %s: %w", sum, err))
+	}
+	return sum, size, nil
+}
+
+func (b BlobStoreDisk) Open(hash string) (*os.File, error) {
+	file, err := os.Open(b.path(hash))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("blob %s: %w", hash, model.ErrNotFound)
+	}
+	if err != nil {
+		return nil, storageError(fmt.Errorf("failed to open blob %s: %w", hash, err))
+	}
+	return file, nil
+}
+
+func (b BlobStoreDisk) Delete(hash string) error {
+	if err := os.Remove(b.path(hash)); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return storageError(fmt.Errorf("failed to delete blob %s: %w", hash, err))
+	}
+	return nil
+}
+
+// List returns the hash of every stored blob along with its last modification time.
+func (b BlobStoreDisk) List() (map[string]time.Time, error) {
+	blobs := make(map[string]time.Time)
+	err := filepath.WalkDir(b.dir, func(path string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if entry.IsDir() || filepath.Dir(path) == b.dir {
+			return nil
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return err
+		}
+		blobs[entry.Name()] = info.ModTime()
+		return nil
+	})
+	if err != nil {
+		return nil, storageError(fmt.Errorf("failed to list blobs: %w", err))
+	}
+	return blobs, nil
+}
+
+func (b BlobStoreDisk) path(hash string) string {
+	if len(hash) < 2 {
+		return filepath.Join(b.dir, "_", hash)
+	}
+	return filepath.Join(b.dir, hash[:2], hash)
+}
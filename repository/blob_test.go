@@ -0,0 +1,55 @@
+package repository
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func Test_BlobStoreDisk_PutDeduplicates(t *testing.T) {
+	const dir = "Test_BlobStoreDisk"
+	defer os.RemoveAll(dir)
+
+	store, err := NewBlobStoreDisk(dir)
+	if err != nil {
+		t.Fatalf("failed to create BlobStoreDisk: %s", err)
+	}
+
+	firstHash, size, err := store.Put(strings.NewReader("lorem ipsum"))
+	if err != nil {
+		t.Fatalf("expected Put call to return no errors, got \"%s\"", err)
+	}
+
+	if size != int64(len("lorem ipsum")) {
+		t.Errorf("expected size to be %d, got %d", len("lorem ipsum"), size)
+	}
+
+	secondHash, _, err := store.Put(strings.NewReader("lorem ipsum"))
+	if err != nil {
+		t.Fatalf("expected Put call to return no errors, got \"%s\"", err)
+	}
+
+	if firstHash != secondHash {
+		t.Errorf("expected identical content to have the same hash, got %s and %s", firstHash, secondHash)
+	}
+
+	blobs, err := store.List()
+	if err != nil {
+		t.Fatalf("expected List call to return no errors, got \"%s\"", err)
+	}
+
+	if len(blobs) != 1 {
+		t.Errorf("expected one blob to be stored, got %d", len(blobs))
+	}
+
+	file, err := store.Open(firstHash)
+	if err != nil {
+		t.Fatalf("expected Open call to return no errors, got \"%s\"", err)
+	}
+	defer file.Close()
+
+	if content, _ := io.ReadAll(file); string(content) != "lorem ipsum" {
+		t.Errorf("expected blob content to be kept, got %s", content)
+	}
+}
@@ -0,0 +1,134 @@
+package repository
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+	"slices"
+)
+
+type CommentRepositoryFile struct {
+	file       *jsonFile[model.Comment]
+	sequenceId int
+}
+
+func NewCommentRepositoryFile(path string) (*CommentRepositoryFile, error) {
+	file, err := newJSONFile[model.Comment](path)
+	if err != nil {
+		return nil, err
+	}
+
+	comments, err := file.all()
+	if err != nil {
+		return nil, err
+	}
+
+	sequenceId := 0
+	for _, comment := range comments {
+		sequenceId = max(sequenceId, comment.Id)
+	}
+
+	return &CommentRepositoryFile{file: file, sequenceId: sequenceId}, nil
+}
+
+func (r *CommentRepositoryFile) AddComment(comment model.Comment) (model.Comment, error) {
+	err := r.file.update(func(comments []model.Comment) ([]model.Comment, error) {
+		r.sequenceId++
+		comment.Id = r.sequenceId
+		return append(comments, comment), nil
+	})
+	if err != nil {
+		return model.Comment{}, fmt.Errorf("failed to add comment: %w", err)
+	}
+	return comment, nil
+}
+
+func (r *CommentRepositoryFile) GetAllComments() ([]model.Comment, error) {
+	comments, err := r.file.all()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve comments: %w", err)
+	}
+	return comments, nil
+}
+
+func (r *CommentRepositoryFile) GetComments(taskId int) ([]model.Comment, error) {
+	comments, err := r.GetAllComments()
+	if err != nil {
+		return nil, err
+	}
+
+	taskComments := make([]model.Comment, 0)
+	for _, comment := range comments {
+		if comment.TaskId == taskId {
+			taskComments = append(taskComments, comment)
+		}
+	}
+	return taskComments, nil
+}
+
+func (r *CommentRepositoryFile) GetComment(id int) (model.Comment, error) {
+	comments, err := r.file.all()
+	if err != nil {
+		return model.Comment{}, fmt.Errorf("failed to retrieve comments: %w", err)
+	}
+
+	for _, comment := range comments {
+		if comment.Id == id {
+			return comment, nil
+		}
+	}
+	return model.Comment{}, fmt.Errorf("comment with id %d: %w", id, model.ErrNotFound)
+}
+
+// CountComments returns the number of comments of every task that has any.
+func (r *CommentRepositoryFile) CountComments() (map[int]int, error) {
+	comments, err := r.file.all()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve comments: %w", err)
+	}
+
+	counts := make(map[int]int)
+	for _, comment := range comments {
+		counts[comment.TaskId]++
+	}
+	return counts, nil
+}
+
+func (r *CommentRepositoryFile) UpdateComment(comment model.Comment) error {
+	err := r.file.update(func(comments []model.Comment) ([]model.Comment, error) {
+		index := slices.IndexFunc(comments, func(c model.Comment) bool { return c.Id == comment.Id })
+		if index == -1 {
+			return nil, fmt.Errorf("comment with id %d: %w", comment.Id, model.ErrNotFound)
+		}
+		comments[index] = comment
+		return comments, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update comment %d: %w", comment.Id, err)
+	}
+	return nil
+}
+
+func (r *CommentRepositoryFile) DeleteComment(id int) error {
+	err := r.file.update(func(comments []model.Comment) ([]model.Comment, error) {
+		index := slices.IndexFunc(comments, func(c model.Comment) bool { return c.Id == id })
+		if index == -1 {
+			return nil, fmt.Errorf("comment with id %d: %w", id, model.ErrNotFound)
+		}
+		return slices.Delete(comments, index, index+1), nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete comment %d: %w", id, err)
+	}
+	return nil
+}
+
+// DeleteTaskComments removes every comment of the task taskId.
+func (r *CommentRepositoryFile) DeleteTaskComments(taskId int) error {
+	err := r.file.update(func(comments []model.Comment) ([]model.Comment, error) {
+		return slices.DeleteFunc(comments, func(c model.Comment) bool { return c.TaskId == taskId }), nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete comments of task %d: %w", taskId, err)
+	}
+	return nil
+}
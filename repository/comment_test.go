@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"go-task-tracker/model"
+	"testing"
+)
+
+func Test_DeleteTaskComments(t *testing.T) {
+	const fileName = "Test_DeleteTaskComments.json"
+	defer removeTestFile(fileName)
+
+	r, err := NewCommentRepositoryFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to create CommentRepositoryFile: %s", err)
+	}
+
+	for _, taskId := range []int{1, 2, 1} {
+		if _, err := r.AddComment(model.Comment{TaskId: taskId, Body: "Lorem"}); err != nil {
+			t.Fatalf("failed to call AddComment: \"%v\"", err)
+		}
+	}
+
+	if err = r.DeleteTaskComments(1); err != nil {
+		t.Fatalf("expected DeleteTaskComments call to return no errors, got \"%s\"", err)
+	}
+
+	counts, err := r.CountComments()
+	if err != nil {
+		t.Fatalf("expected CountComments call to return no errors, got \"%s\"", err)
+	}
+
+	if counts[1] != 0 || counts[2] != 1 {
+		t.Errorf("expected only the comment of task 2 to be left, got %v", counts)
+	}
+}
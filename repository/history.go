@@ -0,0 +1,70 @@
+package repository
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+)
+
+type HistoryRepositoryFile struct {
+	file       *jsonFile[model.TaskRevision]
+	sequenceId int
+}
+
+func NewHistoryRepositoryFile(path string) (*HistoryRepositoryFile, error) {
+	file, err := newJSONFile[model.TaskRevision](path)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := file.all()
+	if err != nil {
+		return nil, err
+	}
+
+	sequenceId := 0
+	for _, revision := range revisions {
+		sequenceId = max(sequenceId, revision.Id)
+	}
+
+	return &HistoryRepositoryFile{file: file, sequenceId: sequenceId}, nil
+}
+
+func (r *HistoryRepositoryFile) AddRevision(revision model.TaskRevision) error {
+	err := r.file.update(func(revisions []model.TaskRevision) ([]model.TaskRevision, error) {
+		r.sequenceId++
+		revision.Id = r.sequenceId
+		return append(revisions, revision), nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to add revision of task %d: %w", revision.TaskId, err)
+	}
+	return nil
+}
+
+// GetAllRevisions returns every revision in the order they were recorded.
+func (r *HistoryRepositoryFile) GetAllRevisions() ([]model.TaskRevision, error) {
+	revisions, err := r.file.all()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve revisions: %w", err)
+	}
+	return revisions, nil
+}
+
+func (r *HistoryRepositoryFile) GetRevisions(taskId int) ([]model.TaskRevision, error) {
+	return r.GetRevisionsOf([]int{taskId})
+}
+
+// GetRevisionsOf returns the revisions of the tasks taskIds in the order they were recorded,
+// without reading the revisions of other tasks into memory.
+func (r *HistoryRepositoryFile) GetRevisionsOf(taskIds []int) ([]model.TaskRevision, error) {
+	ids := make(map[int]bool, len(taskIds))
+	for _, id := range taskIds {
+		ids[id] = true
+	}
+
+	revisions, err := r.file.filter(func(revision model.TaskRevision) bool { return ids[revision.TaskId] })
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve revisions: %w", err)
+	}
+	return revisions, nil
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"go-task-tracker/model"
+	"slices"
+	"testing"
+)
+
+func Test_GetRevisionsOf(t *testing.T) {
+	const fileName = "Test_GetRevisionsOf.json"
+	defer removeTestFile(fileName)
+
+	r, err := NewHistoryRepositoryFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to create HistoryRepositoryFile: %s", err)
+	}
+
+	for _, taskId := range []int{1, 2, 3, 1, 2} {
+		if err := r.AddRevision(model.TaskRevision{TaskId: taskId, Type: model.TaskUpdated}); err != nil {
+			t.Fatalf("failed to call AddRevision: \"%v\"", err)
+		}
+	}
+
+	revisions, err := r.GetRevisionsOf([]int{1, 3})
+	if err != nil {
+		t.Fatalf("expected GetRevisionsOf call to return no errors, got \"%s\"", err)
+	}
+
+	var ids []int
+	for _, revision := range revisions {
+		ids = append(ids, revision.Id)
+	}
+	if !slices.Equal(ids, []int{1, 3, 4}) {
+		t.Errorf("expected revisions [1 3 4] of tasks 1 and 3, got %v", ids)
+	}
+}
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+	"slices"
+)
+
+type ProjectRepositoryFile struct {
+	file       *jsonFile[model.Project]
+	sequenceId int
+}
+
+func NewProjectRepositoryFile(path string) (*ProjectRepositoryFile, error) {
+	file, err := newJSONFile[model.Project](path)
+	if err != nil {
+		return nil, err
+	}
+
+	projects, err := file.all()
+	if err != nil {
+		return nil, err
+	}
+
+	sequenceId := 0
+	for _, project := range projects {
+		sequenceId = max(sequenceId, project.Id)
+	}
+
+	return &ProjectRepositoryFile{file: file, sequenceId: sequenceId}, nil
+}
+
+// AddProject stores project, failing when another project already uses its key.
+func (r *ProjectRepositoryFile) AddProject(project model.Project) (model.Project, error) {
+	err := r.file.update(func(projects []model.Project) ([]model.Project, error) {
+		if slices.ContainsFunc(projects, func(p model.Project) bool { return p.Key == project.Key }) {
+			return nil, fmt.Errorf("%w: project with key %s already exists", model.ErrConflict, project.Key)
+		}
+		r.sequenceId++
+		project.Id = r.sequenceId
+		return append(projects, project), nil
+	})
+	if err != nil {
+		return model.Project{}, fmt.Errorf("failed to add project: %w", err)
+	}
+	return project, nil
+}
+
+func (r *ProjectRepositoryFile) GetAllProjects() ([]model.Project, error) {
+	projects, err := r.file.all()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve projects: %w", err)
+	}
+	return projects, nil
+}
+
+func (r *ProjectRepositoryFile) GetProject(id int) (model.Project, error) {
+	return r.findProject(func(p model.Project) bool { return p.Id == id }, fmt.Sprintf("id %d", id))
+}
+
+func (r *ProjectRepositoryFile) GetProjectByKey(key string) (model.Project, error) {
+	return r.findProject(func(p model.Project) bool { return p.Key == key }, "key "+key)
+}
+
+func (r *ProjectRepositoryFile) findProject(matches func(model.Project) bool, description string) (model.Project, error) {
+	projects, err := r.GetAllProjects()
+	if err != nil {
+		return model.Project{}, err
+	}
+
+	index := slices.IndexFunc(projects, matches)
+	if index == -1 {
+		return model.Project{}, fmt.Errorf("project with %s: %w", description, model.ErrNotFound)
+	}
+	return projects[index], nil
+}
+
+// UpdateProject replaces the stored project with the same id as project. The task
+// sequence is kept, it only moves through NextTaskNumber.
+func (r *ProjectRepositoryFile) UpdateProject(project model.Project) error {
+	err := r.file.update(func(projects []model.Project) ([]model.Project, error) {
+		index := slices.IndexFunc(projects, func(p model.Project) bool { return p.Id == project.Id })
+		if index == -1 {
+			return nil, fmt.Errorf("project with id %d: %w", project.Id, model.ErrNotFound)
+		}
+		project.TaskSequence = projects[index].TaskSequence
+		projects[index] = project
+		return projects, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update project %d: %w", project.Id, err)
+	}
+	return nil
+}
+
+func (r *ProjectRepositoryFile) DeleteProject(id int) error {
+	err := r.file.update(func(projects []model.Project) ([]model.Project, error) {
+		index := slices.IndexFunc(projects, func(p model.Project) bool { return p.Id == id })
+		if index == -1 {
+			return nil, fmt.Errorf("project with id %d: %w", id, model.ErrNotFound)
+		}
+		return slices.Delete(projects, index, index+1), nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete project %d: %w", id, err)
+	}
+	return nil
+}
+
+// NextTaskNumber increments the task sequence of the project id and returns it.
+func (r *ProjectRepositoryFile) NextTaskNumber(id int) (int, error) {
+	number := 0
+	err := r.file.update(func(projects []model.Project) ([]model.Project, error) {
+		index := slices.IndexFunc(projects, func(p model.Project) bool { return p.Id == id })
+		if index == -1 {
+			return nil, fmt.Errorf("project with id %d: %w", id, model.ErrNotFound)
+		}
+		projects[index].TaskSequence++
+		number = projects[index].TaskSequence
+		return projects, nil
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get next task number of project %d: %w", id, err)
+	}
+	return number, nil
+}
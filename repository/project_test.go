@@ -0,0 +1,59 @@
+package repository
+
+import (
+	"go-task-tracker/model"
+	"testing"
+)
+
+func Test_AddProject_DuplicatedKey(t *testing.T) {
+	const fileName = "Test_AddProject_DuplicatedKey.json"
+	defer removeTestFile(fileName)
+
+	r, err := NewProjectRepositoryFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to create ProjectRepositoryFile: %s", err)
+	}
+
+	if _, err = r.AddProject(model.Project{Key: "OPS"}); err != nil {
+		t.Fatalf("failed to call AddProject: \"%v\"", err)
+	}
+
+	if _, err = r.AddProject(model.Project{Key: "OPS"}); err == nil {
+		t.Errorf("expected AddProject call with a used key to return an error")
+	}
+}
+
+func Test_NextTaskNumber(t *testing.T) {
+	const fileName = "Test_NextTaskNumber.json"
+	defer removeTestFile(fileName)
+
+	r, err := NewProjectRepositoryFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to create ProjectRepositoryFile: %s", err)
+	}
+
+	project, err := r.AddProject(model.Project{Key: "OPS"})
+	if err != nil {
+		t.Fatalf("failed to call AddProject: \"%v\"", err)
+	}
+
+	for expected := 1; expected <= 3; expected++ {
+		number, err := r.NextTaskNumber(project.Id)
+		if err != nil {
+			t.Fatalf("expected NextTaskNumber call to return no errors, got \"%s\"", err)
+		}
+
+		if number != expected {
+			t.Errorf("expected task number %d, got %d", expected, number)
+		}
+	}
+
+	project.Name = "Operations"
+	if err = r.UpdateProject(project); err != nil {
+		t.Fatalf("expected UpdateProject call to return no errors, got \"%s\"", err)
+	}
+
+	if project, _ = r.GetProjectByKey("OPS"); project.TaskSequence != 3 {
+		t.Errorf("expected UpdateProject to keep the task sequence, got %d", project.TaskSequence)
+	}
+}
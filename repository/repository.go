@@ -1,21 +1,24 @@
 package repository
 
 import (
-	"bufio"
+	"bytes"
+	"cmp"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"go-task-tracker/model"
+	"io"
+	"math"
 	"os"
-	"regexp"
+	"slices"
+	"strings"
 	"sync"
-	"time"
 )
 
 type TaskRepositoryFile struct {
 	path       string
 	offset     int64
 	sequenceId int
+	index      *taskIndex
 	mutex      sync.Mutex
 }
 
@@ -41,7 +44,15 @@ func NewTaskRepositoryFile(path string) (TaskRepositoryFile, error) {
 		if _, err := file.WriteString(firstLineValue + lastLineValue); err != nil {
 			return TaskRepositoryFile{}, fmt.Errorf("failed to initialize file: %w", err)
 		}
-		return TaskRepositoryFile{path: path, offset: int64(len(firstLineValue)), sequenceId: 0}, nil
+		return TaskRepositoryFile{path: path, offset: int64(len(firstLineValue)), sequenceId: 0, index: newTaskIndex()}, nil
+	}
+
+	if err := migrateTaskFile(file); err != nil {
+		return TaskRepositoryFile{}, err
+	}
+
+	if fileInfo, err = file.Stat(); err != nil {
+		return TaskRepositoryFile{}, fmt.Errorf("failed to get file info: %w", err)
 	}
 
 	lastLineBytes := int64(len(lastLineValue))
@@ -52,7 +63,63 @@ func NewTaskRepositoryFile(path string) (TaskRepositoryFile, error) {
 		panic(fmt.Errorf("failed to create sequence id %s: %w", path, err))
 	}
 
-	return TaskRepositoryFile{path: path, offset: offset, sequenceId: sequenceId}, nil
+	index, offset, err := loadIndex(file, offset)
+	if err != nil {
+		return TaskRepositoryFile{}, err
+	}
+
+	return TaskRepositoryFile{path: path, offset: offset, sequenceId: sequenceId, index: index}, nil
+}
+
+// loadIndex indexes the tasks of file, first rewriting it with one task per line when it
+// isn't laid out that way. It returns the offset AddTask writes at, which changes when
+// the file is rewritten.
+func loadIndex(file *os.File, offset int64) (*taskIndex, int64, error) {
+	content, err := io.ReadAll(io.NewSectionReader(file, 0, math.MaxInt64))
+	if err != nil {
+		return nil, 0, storageError(fmt.Errorf("failed to read file %s: %w", file.Name(), err))
+	}
+
+	if index, ok := indexTasks(content); ok {
+		return index, offset, nil
+	}
+
+	var tasks []model.Task
+	if err := json.Unmarshal(content, &tasks); err != nil {
+		return nil, 0, fmt.Errorf("failed to decode tasks in file %s: %w", file.Name(), err)
+	}
+
+	rewritten := &TaskRepositoryFile{}
+	if err := rewritten.writeTasks(file, tasks); err != nil {
+		return nil, 0, fmt.Errorf("failed to rewrite file %s: %w", file.Name(), err)
+	}
+	return rewritten.index, rewritten.offset, nil
+}
+
+// migrateTaskFile rewrites file when it holds timestamps in the legacy zone-less layout,
+// which are decoded as local time and written back in RFC 3339. It leaves file positioned
+// at its beginning.
+func migrateTaskFile(file *os.File) error {
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return storageError(fmt.Errorf("failed to read file %s: %w", file.Name(), err))
+	}
+
+	if model.IsLegacyDateTime(content) {
+		var tasks []model.Task
+		if err := json.Unmarshal(content, &tasks); err != nil {
+			return fmt.Errorf("failed to decode tasks in file %s: %w", file.Name(), err)
+		}
+
+		if err := (&TaskRepositoryFile{}).writeTasks(file, tasks); err != nil {
+			return fmt.Errorf("failed to migrate file %s: %w", file.Name(), err)
+		}
+	}
+
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return storageError(fmt.Errorf("failed to seek to beginning of file: %w", err))
+	}
+	return nil
 }
 
 func loadSequenceId(file *os.File) (int, error) {
@@ -69,13 +136,14 @@ func loadSequenceId(file *os.File) (int, error) {
 	return tasks[len(tasks)-1].Id, nil
 }
 
-func (r *TaskRepositoryFile) AddTask(task model.Task) error {
+// AddTask appends task to the file and returns it with the id it was given.
+func (r *TaskRepositoryFile) AddTask(task model.Task) (model.Task, error) {
 	r.mutex.Lock()
 	defer r.mutex.Unlock()
 
 	file, err := os.OpenFile(r.path, os.O_RDWR, filePerm)
 	if err != nil {
-		return fmt.Errorf("failed to open file %s: %w", r.path, err)
+		return model.Task{}, storageError(fmt.Errorf("failed to open file %s: %w", r.path, err))
 	}
 	defer file.Close()
 
@@ -84,81 +152,65 @@ func (r *TaskRepositoryFile) AddTask(task model.Task) error {
 
 	b, err := json.Marshal(&task)
 	if err != nil {
-		return fmt.Errorf("failed to marshal task %d: %w", task.Id, err)
+		return model.Task{}, fmt.Errorf("failed to marshal task %d: %w", task.Id, err)
 	}
 
 	stringJson := string(b)
+	span := lineSpan{offset: r.offset, length: len(b)}
 	if firstLineSize := int64(len(firstLineValue)); r.offset != firstLineSize {
 		stringJson = fmt.Sprintf(",\n%s%s", stringJson, lastLineValue)
+		span.offset += int64(len(",\n"))
 	} else {
 		stringJson = fmt.Sprintf("%s%s", stringJson, lastLineValue)
 	}
 
 	writtenBytes, err := file.WriteAt([]byte(stringJson), r.offset)
 	if err != nil {
-		return fmt.Errorf("failed to write to file: %w", err)
+		return model.Task{}, storageError(fmt.Errorf("failed to write to file: %w", err))
 	}
 
 	r.offset += int64(writtenBytes) - int64(len(lastLineValue))
-	return nil
+	r.index.add(task, span)
+	return task, nil
 }
 
-func (r *TaskRepositoryFile) UpdateTask(id int, updatedTask model.UpdateTask) error {
+// UpdateTask applies updatedTask to the task id and returns the task as stored. Only the
+// line of the task is rewritten, the other lines are written back as they were.
+func (r *TaskRepositoryFile) UpdateTask(id int, updatedTask model.UpdateTask) (model.Task, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 
-	file, err := os.OpenFile(r.path, os.O_RDWR, 0644)
+	file, err := os.OpenFile(r.path, os.O_RDWR, filePerm)
 	if err != nil {
-		return fmt.Errorf("failed to open file %s: %w", r.path, err)
+		return model.Task{}, storageError(fmt.Errorf("failed to open file %s: %w", r.path, err))
 	}
 	defer file.Close()
 
-	var lines []string
-	scanner := bufio.NewScanner(file)
-	regexExpr := fmt.Sprintf("(\"Id\":%d)", id)
-
-	taskExists := false
-	for scanner.Scan() {
-		line := scanner.Text()
-		if matches, _ := regexp.MatchString(regexExpr, line); matches {
-			taskExists = matches
-			var task model.Task
-			if err = json.Unmarshal([]byte(line[:len(line)-1]), &task); err != nil {
-				return fmt.Errorf("failed to unmarshal json: %w", err)
-			}
-
-			if updatedTask.Description != nil {
-				task.Description = *updatedTask.Description
-			}
-
-			if updatedTask.Status != nil {
-				task.Status = *updatedTask.Status
-			}
-
-			task.UpdatedAt = model.DateTime(time.Now())
-
-			jsonBytes, err := json.Marshal(&task)
-			if err != nil {
-				return fmt.Errorf("failed to marshal json: %w", err)
-			}
-			line = string(jsonBytes) + line[len(line)-1:]
+	var task model.Task
+	err = r.rewriteLines(file, id, func(lineId int, line []byte) ([]byte, error) {
+		if lineId != id {
+			return line, nil
 		}
-		lines = append(lines, line)
-	}
 
-	if !taskExists {
-		return errors.New(fmt.Sprintf("task with %d does not exists", id))
-	}
+		if err := json.Unmarshal(line, &task); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal json: %w", err)
+		}
 
-	if err := truncateAndWrite(file, lines); err != nil {
-		return fmt.Errorf("failed to update task %d: %w", id, err)
+		updatedTask.Apply(&task)
+		task.UpdatedAt = model.Now()
+		return json.Marshal(&task)
+	})
+	if err != nil {
+		return model.Task{}, fmt.Errorf("failed to update task %d: %w", id, err)
 	}
 
-	return nil
+	return task, nil
 }
 
 func (r *TaskRepositoryFile) GetAllTasks() ([]model.Task, error) {
 	file, err := os.Open(r.path)
 	if err != nil {
-		return nil, fmt.Errorf("failed to retrieve tasks: %w", err)
+		return nil, storageError(fmt.Errorf("failed to retrieve tasks: %w", err))
 	}
 	defer file.Close()
 
@@ -172,42 +224,71 @@ func (r *TaskRepositoryFile) GetAllTasks() ([]model.Task, error) {
 
 }
 
-func (r *TaskRepositoryFile) DeleteTask(id int) error {
-	file, err := os.OpenFile(r.path, os.O_RDWR, 0644)
+// GetTask returns the task id, reading its line of the file only.
+func (r *TaskRepositoryFile) GetTask(id int) (model.Task, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	span, ok := r.index.lines[id]
+	if !ok {
+		return model.Task{}, fmt.Errorf("task with id %d: %w", id, model.ErrNotFound)
+	}
+
+	file, err := os.Open(r.path)
 	if err != nil {
-		return fmt.Errorf("failed to retrieve tasks: %w", err)
+		return model.Task{}, storageError(fmt.Errorf("failed to retrieve tasks: %w", err))
 	}
 	defer file.Close()
 
-	var lines []string
-	scanner := bufio.NewScanner(file)
-	regexExpr := fmt.Sprintf("(\"Id\":%d)", id)
+	return readTask(file, span)
+}
 
-	taskToDeleteIndex := -1
-	for scanner.Scan() {
-		line := scanner.Text()
-		if matches, _ := regexp.MatchString(regexExpr, line); matches {
-			taskToDeleteIndex = len(lines)
-		}
-		lines = append(lines, line)
-	}
+// FindTasks returns the tasks selected by every lookup, reading the lines of those tasks
+// only. Tasks are returned in id order.
+func (r *TaskRepositoryFile) FindTasks(lookups []model.TaskLookup) ([]model.Task, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 
-	if err = scanner.Err(); err != nil {
-		return fmt.Errorf("failed to scan file: %w", err)
+	ids := r.index.lookup(lookups)
+	if len(ids) == 0 {
+		return nil, nil
 	}
 
-	if taskToDeleteIndex == -1 {
-		return fmt.Errorf("task with id %d does not exists", id)
+	file, err := os.Open(r.path)
+	if err != nil {
+		return nil, storageError(fmt.Errorf("failed to retrieve tasks: %w", err))
 	}
+	defer file.Close()
 
-	if len(lines) > 1 && taskToDeleteIndex == len(lines)-2 {
-		lineEndsWithComma := regexp.MustCompile(",$")
-		lines[taskToDeleteIndex-1] = lineEndsWithComma.ReplaceAllString(lines[taskToDeleteIndex-1], "")
+	tasks := make([]model.Task, 0, len(ids))
+	for _, id := range ids {
+		task, err := readTask(file, r.index.lines[id])
+		if err != nil {
+			return nil, err
+		}
+		tasks = append(tasks, task)
 	}
+	return tasks, nil
+}
 
-	lines = append(lines[:taskToDeleteIndex], lines[taskToDeleteIndex+1:]...)
+// DeleteTask removes the line of the task id, the other lines are written back as they were.
+func (r *TaskRepositoryFile) DeleteTask(id int) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
 
-	if err := truncateAndWrite(file, lines); err != nil {
+	file, err := os.OpenFile(r.path, os.O_RDWR, filePerm)
+	if err != nil {
+		return storageError(fmt.Errorf("failed to retrieve tasks: %w", err))
+	}
+	defer file.Close()
+
+	err = r.rewriteLines(file, id, func(lineId int, line []byte) ([]byte, error) {
+		if lineId == id {
+			return nil, nil
+		}
+		return line, nil
+	})
+	if err != nil {
 		return fmt.Errorf("failed to delete task %d: %w", id, err)
 	}
 
@@ -215,25 +296,145 @@ func (r *TaskRepositoryFile) DeleteTask(id int) error {
 
 }
 
-func truncateAndWrite(file *os.File, lines []string) error {
-	if _, err := file.Seek(0, 0); err != nil {
-		return fmt.Errorf("failed to seek to beginning of file: %w", err)
+// RemoveTasks deletes the tasks ids from the file in a single write. Unlike DeleteTask it
+// keeps the links other tasks have to them, as removed tasks remain readable in the archive.
+func (r *TaskRepositoryFile) RemoveTasks(ids []int) error {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	file, err := os.OpenFile(r.path, os.O_RDWR, filePerm)
+	if err != nil {
+		return storageError(fmt.Errorf("failed to retrieve tasks: %w", err))
+	}
+	defer file.Close()
+
+	tasks, err := decodeTasks(file)
+	if err != nil {
+		return err
 	}
 
-	if err := file.Truncate(0); err != nil {
-		return fmt.Errorf("failed to truncate file: %w", err)
+	tasks = slices.DeleteFunc(tasks, func(task model.Task) bool { return slices.Contains(ids, task.Id) })
+	if err := r.writeTasks(file, tasks); err != nil {
+		return fmt.Errorf("failed to remove tasks: %w", err)
+	}
+	return nil
+}
+
+// ReserveIds makes sure tasks added from now on get ids greater than lastId, which is used
+// for ids of tasks kept outside of the file.
+func (r *TaskRepositoryFile) ReserveIds(lastId int) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.sequenceId = max(r.sequenceId, lastId)
+}
+
+func decodeTasks(file *os.File) ([]model.Task, error) {
+	var tasks []model.Task
+	if err := json.NewDecoder(file).Decode(&tasks); err != nil {
+		return nil, fmt.Errorf("failed to decode tasks in file %s: %w", file.Name(), err)
+	}
+	return tasks, nil
+}
+
+func indexOfTask(tasks []model.Task, id int) int {
+	for i, task := range tasks {
+		if task.Id == id {
+			return i
+		}
 	}
+	return -1
+}
 
-	writer := bufio.NewWriter(file)
-	for _, line := range lines {
-		if _, err := writer.WriteString(line + "\n"); err != nil {
-			return fmt.Errorf("failed to write in buffer: %w", err)
+// writeTasks replaces the content of file with tasks, one task per line, moves the
+// offset used by AddTask to the end of the new content and indexes it.
+func (r *TaskRepositoryFile) writeTasks(file *os.File, tasks []model.Task) error {
+	index := newTaskIndex()
+	offset := int64(len(firstLineValue))
+	lines := make([]string, 0, len(tasks))
+	for _, task := range tasks {
+		b, err := json.Marshal(&task)
+		if err != nil {
+			return fmt.Errorf("failed to marshal task %d: %w", task.Id, err)
 		}
+		lines = append(lines, string(b))
+
+		index.add(task, lineSpan{offset: offset, length: len(b)})
+		offset += int64(len(b) + len(",\n"))
+	}
+	content := firstLineValue + strings.Join(lines, ",\n") + lastLineValue
+
+	if err := truncateAndWrite(file, []byte(content)); err != nil {
+		return err
+	}
+
+	r.offset = int64(len(content) - len(lastLineValue))
+	r.index = index
+	return nil
+}
+
+// rewriteLines replaces the content of file with the lines of its tasks as changed by
+// change, which gets the id and JSON of each task and returns the JSON to write instead,
+// or nil to drop the task. It fails with model.ErrNotFound, leaving file as it is, when no
+// line holds the task id. The file is then indexed again.
+func (r *TaskRepositoryFile) rewriteLines(file *os.File, id int, change func(lineId int, line []byte) ([]byte, error)) error {
+	content, err := io.ReadAll(file)
+	if err != nil {
+		return storageError(fmt.Errorf("failed to read file %s: %w", file.Name(), err))
+	}
+
+	index, ok := indexTasks(content)
+	if !ok {
+		return fmt.Errorf("file %s doesn't hold one task per line", file.Name())
 	}
 
-	if err := writer.Flush(); err != nil {
-		return fmt.Errorf("failed to flush buffer: %w", err)
+	if _, ok := index.lines[id]; !ok {
+		return fmt.Errorf("task with id %d: %w", id, model.ErrNotFound)
 	}
 
+	ids := make([]int, 0, len(index.lines))
+	for lineId := range index.lines {
+		ids = append(ids, lineId)
+	}
+	slices.SortFunc(ids, func(a, b int) int { return cmp.Compare(index.lines[a].offset, index.lines[b].offset) })
+
+	lines := make([][]byte, 0, len(ids))
+	for _, lineId := range ids {
+		span := index.lines[lineId]
+		line, err := change(lineId, content[span.offset:span.offset+int64(span.length)])
+		if err != nil {
+			return err
+		}
+		if line != nil {
+			lines = append(lines, line)
+		}
+	}
+	content = slices.Concat([]byte(firstLineValue), bytes.Join(lines, []byte(",\n")), []byte(lastLineValue))
+
+	if index, ok = indexTasks(content); !ok {
+		return fmt.Errorf("failed to index file %s", file.Name())
+	}
+
+	if err := truncateAndWrite(file, content); err != nil {
+		return err
+	}
+
+	r.offset = int64(len(content) - len(lastLineValue))
+	r.index = index
+	return nil
+}
+
+func truncateAndWrite(file *os.File, content []byte) error {
+	if _, err := file.Seek(0, 0); err != nil {
+		return storageError(fmt.Errorf("failed to seek to beginning of file: %w", err))
+	}
+
+	if err := file.Truncate(0); err != nil {
+		return storageError(fmt.Errorf("failed to truncate file: %w", err))
+	}
+
+	if _, err := file.Write(content); err != nil {
+		return storageError(fmt.Errorf("failed to write to file: %w", err))
+	}
 	return nil
 }
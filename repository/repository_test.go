@@ -3,9 +3,11 @@ package repository
 import (
 	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"go-task-tracker/model"
 	"os"
+	"slices"
 	"testing"
 	"time"
 )
@@ -72,7 +74,7 @@ func Test_AddTask(t *testing.T) {
 		UpdatedAt:   model.DateTime(time.Now()),
 	}
 
-	if err = r.AddTask(task); err != nil {
+	if _, err = r.AddTask(task); err != nil {
 		t.Fatalf("failed to call AddTask: \"%v\"", err)
 	}
 
@@ -117,7 +119,7 @@ func Test_UpdateTask(t *testing.T) {
 
 	task := tasks[0]
 
-	if err = repository.UpdateTask(task.Id, updatedTask); err != nil {
+	if _, err = repository.UpdateTask(task.Id, updatedTask); err != nil {
 		t.Fatalf("expected call to CreateTask to not return error, got \"%v\"", err)
 	}
 
@@ -140,7 +142,7 @@ func Test_UpdateTask(t *testing.T) {
 		}
 	}
 
-	if taskInFile == (model.Task{}) {
+	if taskInFile.Id != task.Id {
 		t.Fatalf("expected to find task with id %d", task.Id)
 	}
 
@@ -275,3 +277,208 @@ func removeTestFile(fileName string) {
 		panic(fmt.Errorf("failed to remove file %s: %w", fileName, err))
 	}
 }
+
+func removeTestDir(dirName string) {
+	err := os.RemoveAll(dirName)
+	if err != nil {
+		panic(fmt.Errorf("failed to remove directory %s: %w", dirName, err))
+	}
+}
+
+func Test_UpdateTask_LastTaskThenAdd(t *testing.T) {
+	const fileName = "Test_UpdateTask_LastTaskThenAdd.json"
+	defer removeTestFile(fileName)
+
+	addTasksToFileOrFail(newTasks(2), fileName, t)
+
+	repository, err := NewTaskRepositoryFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to create TaskRepositoryFile: %s", err)
+	}
+
+	assignee := 7
+	if _, err = repository.UpdateTask(2, model.UpdateTask{AssigneeId: &assignee}); err != nil {
+		t.Fatalf("expected UpdateTask call to return no errors, got \"%s\"", err)
+	}
+
+	if _, err = repository.AddTask(model.Task{Description: "New"}); err != nil {
+		t.Fatalf("expected AddTask call to return no errors, got \"%s\"", err)
+	}
+
+	tasks, err := repository.GetAllTasks()
+	if err != nil {
+		t.Fatalf("expected file to stay valid json, got \"%s\"", err)
+	}
+
+	if len(tasks) != 3 {
+		t.Fatalf("expected 3 tasks, got %d", len(tasks))
+	}
+
+	if tasks[1].AssigneeId != assignee {
+		t.Errorf("expected task 2 to be assigned to %d, got %d", assignee, tasks[1].AssigneeId)
+	}
+}
+
+func Test_DeleteTask_KeepsOtherTasks(t *testing.T) {
+	const fileName = "Test_DeleteTask_KeepsOtherTasks.json"
+	defer removeTestFile(fileName)
+
+	tasks := newTasks(3)
+	tasks[0].Links = []model.TaskLink{{Type: model.Blocks, TaskId: 3}}
+	tasks[2].Links = []model.TaskLink{{Type: model.BlockedBy, TaskId: 1}}
+	addTasksToFileOrFail(tasks, fileName, t)
+
+	repository, err := NewTaskRepositoryFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to create TaskRepositoryFile: %s", err)
+	}
+
+	if err = repository.DeleteTask(1); err != nil {
+		t.Fatalf("expected DeleteTask call to return no errors, got \"%s\"", err)
+	}
+
+	remaining, err := repository.GetAllTasks()
+	if err != nil {
+		t.Fatalf("failed to get tasks: %s", err)
+	}
+
+	// links are removed by the service, which records the change of every linked task
+	if len(remaining) != 2 || remaining[0].Id != 2 || !slices.Equal(remaining[1].Links, tasks[2].Links) {
+		t.Errorf("expected tasks 2 and 3 to be kept as they were, got %+v", remaining)
+	}
+}
+
+func Test_UpdateTask_NotFound(t *testing.T) {
+	const fileName = "Test_UpdateTask_NotFound.json"
+	defer removeTestFile(fileName)
+
+	addTasksToFileOrFail(newTasks(2), fileName, t)
+
+	repository, err := NewTaskRepositoryFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to create TaskRepositoryFile: %s", err)
+	}
+
+	if _, err = repository.UpdateTask(99, model.UpdateTask{}); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("expected UpdateTask call with an unknown id to return model.ErrNotFound, got \"%v\"", err)
+	}
+
+	if err = repository.DeleteTask(99); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("expected DeleteTask call with an unknown id to return model.ErrNotFound, got \"%v\"", err)
+	}
+}
+
+func Test_GetTask(t *testing.T) {
+	const fileName = "Test_GetTask.json"
+	defer removeTestFile(fileName)
+
+	tasks := newTasks(3)
+	addTasksToFileOrFail(tasks, fileName, t)
+
+	repository, err := NewTaskRepositoryFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to create TaskRepositoryFile: %s", err)
+	}
+
+	task, err := repository.GetTask(2)
+	if err != nil {
+		t.Fatalf("expected GetTask call to return no errors, got \"%s\"", err)
+	}
+
+	if task.Id != 2 || task.Description != tasks[1].Description {
+		t.Errorf("expected task %+v, got %+v", tasks[1], task)
+	}
+
+	if _, err = repository.GetTask(4); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("expected GetTask call with an unknown id to return model.ErrNotFound, got \"%v\"", err)
+	}
+}
+
+func Test_FindTasks(t *testing.T) {
+	const fileName = "Test_FindTasks.json"
+	defer removeTestFile(fileName)
+
+	tasks := newTasks(3)
+	tasks[0].Tags = []string{"backend"}
+	tasks[1].Status = model.Done
+	tasks[2].Tags = []string{"backend", "flaky"}
+	addTasksToFileOrFail(tasks, fileName, t)
+
+	repository, err := NewTaskRepositoryFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to create TaskRepositoryFile: %s", err)
+	}
+
+	if _, err = repository.AddTask(model.Task{Description: "added", Tags: []string{"backend"}}); err != nil {
+		t.Fatalf("failed to call AddTask: \"%v\"", err)
+	}
+
+	done := model.Done
+	if _, err = repository.UpdateTask(1, model.UpdateTask{Status: &done}); err != nil {
+		t.Fatalf("failed to call UpdateTask: \"%v\"", err)
+	}
+
+	var testTable = []struct {
+		lookups  []model.TaskLookup
+		expected []int
+	}{
+		{[]model.TaskLookup{{Index: model.IndexTag, Keys: []string{"backend"}}}, []int{1, 3, 4}},
+		{[]model.TaskLookup{{Index: model.IndexStatus, Keys: []string{"done"}}}, []int{1, 2}},
+		{[]model.TaskLookup{{Index: model.IndexStatus, Keys: []string{"todo", "done"}}, {Index: model.IndexTag, Keys: []string{"backend"}}}, []int{1, 3, 4}},
+		{[]model.TaskLookup{{Index: model.IndexStatus, Keys: []string{"todo"}}, {Index: model.IndexTag, Keys: []string{"flaky"}}}, []int{3}},
+		{[]model.TaskLookup{{Index: model.IndexAssignee, Keys: []string{"7"}}}, nil},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%+v), Expect: %v", testData.lookups, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			found, err := repository.FindTasks(testData.lookups)
+			if err != nil {
+				t.Fatalf("expected FindTasks call to return no errors, got \"%s\"", err)
+			}
+
+			var ids []int
+			for _, task := range found {
+				ids = append(ids, task.Id)
+			}
+			if fmt.Sprint(ids) != fmt.Sprint(testData.expected) {
+				t.Errorf("with input (%+v) got tasks %v, but expected %v", testData.lookups, ids, testData.expected)
+			}
+		})
+	}
+}
+
+func Test_NewTaskRepositoryFile_IndexesRewrittenFile(t *testing.T) {
+	const fileName = "Test_NewTaskRepositoryFile_IndexesRewrittenFile.json"
+	defer removeTestFile(fileName)
+
+	tasks := newTasks(2)
+	tasks[1].Tags = []string{"backend"}
+	content, err := json.MarshalIndent(tasks, "", "  ")
+	if err != nil {
+		t.Fatalf("failed to serialize tasks: %v", err)
+	}
+	if err = os.WriteFile(fileName, content, 0600); err != nil {
+		t.Fatalf("failed to write file %s: %s", fileName, err)
+	}
+
+	repository, err := NewTaskRepositoryFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to create TaskRepositoryFile: %s", err)
+	}
+
+	found, err := repository.FindTasks([]model.TaskLookup{{Index: model.IndexTag, Keys: []string{"backend"}}})
+	if err != nil || len(found) != 1 || found[0].Id != 2 {
+		t.Errorf("expected FindTasks call to return task 2, got %+v, \"%v\"", found, err)
+	}
+
+	if _, err = repository.AddTask(model.Task{Description: "added"}); err != nil {
+		t.Fatalf("failed to call AddTask: \"%v\"", err)
+	}
+
+	if task, err := repository.GetTask(3); err != nil || task.Description != "added" {
+		t.Errorf("expected GetTask call to return the added task, got %+v, \"%v\"", task, err)
+	}
+}
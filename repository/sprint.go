@@ -0,0 +1,79 @@
+package repository
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+	"slices"
+)
+
+type SprintRepositoryFile struct {
+	file       *jsonFile[model.Sprint]
+	sequenceId int
+}
+
+func NewSprintRepositoryFile(path string) (*SprintRepositoryFile, error) {
+	file, err := newJSONFile[model.Sprint](path)
+	if err != nil {
+		return nil, err
+	}
+
+	sprints, err := file.all()
+	if err != nil {
+		return nil, err
+	}
+
+	sequenceId := 0
+	for _, sprint := range sprints {
+		sequenceId = max(sequenceId, sprint.Id)
+	}
+
+	return &SprintRepositoryFile{file: file, sequenceId: sequenceId}, nil
+}
+
+func (r *SprintRepositoryFile) AddSprint(sprint model.Sprint) (model.Sprint, error) {
+	err := r.file.update(func(sprints []model.Sprint) ([]model.Sprint, error) {
+		r.sequenceId++
+		sprint.Id = r.sequenceId
+		return append(sprints, sprint), nil
+	})
+	if err != nil {
+		return model.Sprint{}, fmt.Errorf("failed to add sprint: %w", err)
+	}
+	return sprint, nil
+}
+
+func (r *SprintRepositoryFile) GetAllSprints() ([]model.Sprint, error) {
+	sprints, err := r.file.all()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve sprints: %w", err)
+	}
+	return sprints, nil
+}
+
+func (r *SprintRepositoryFile) GetSprint(id int) (model.Sprint, error) {
+	sprints, err := r.GetAllSprints()
+	if err != nil {
+		return model.Sprint{}, err
+	}
+
+	index := slices.IndexFunc(sprints, func(s model.Sprint) bool { return s.Id == id })
+	if index == -1 {
+		return model.Sprint{}, fmt.Errorf("sprint with id %d: %w", id, model.ErrNotFound)
+	}
+	return sprints[index], nil
+}
+
+func (r *SprintRepositoryFile) UpdateSprint(sprint model.Sprint) error {
+	err := r.file.update(func(sprints []model.Sprint) ([]model.Sprint, error) {
+		index := slices.IndexFunc(sprints, func(s model.Sprint) bool { return s.Id == sprint.Id })
+		if index == -1 {
+			return nil, fmt.Errorf("sprint with id %d: %w", sprint.Id, model.ErrNotFound)
+		}
+		sprints[index] = sprint
+		return sprints, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update sprint %d: %w", sprint.Id, err)
+	}
+	return nil
+}
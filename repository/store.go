@@ -0,0 +1,159 @@
+package repository
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-task-tracker/model"
+	"os"
+	"strings"
+	"sync"
+)
+
+// jsonFile persists a list of records in the same layout used for tasks:
+// a JSON array holding one record per line.
+type jsonFile[T any] struct {
+	path  string
+	mutex sync.Mutex
+}
+
+func newJSONFile[T any](path string) (*jsonFile[T], error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, filePerm)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer file.Close()
+
+	fileInfo, err := file.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get file info: %w", err)
+	}
+
+	if fileInfo.Size() == 0 {
+		if _, err := file.WriteString(firstLineValue + lastLineValue); err != nil {
+			return nil, fmt.Errorf("failed to initialize file: %w", err)
+		}
+	}
+
+	f := &jsonFile[T]{path: path}
+	if err := f.migrate(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// migrate rewrites the file when it holds timestamps in the legacy zone-less layout, which
+// are decoded as local time and written back in RFC 3339.
+func (f *jsonFile[T]) migrate() error {
+	content, err := os.ReadFile(f.path)
+	if err != nil {
+		return storageError(fmt.Errorf("failed to read file %s: %w", f.path, err))
+	}
+
+	if !model.IsLegacyDateTime(content) {
+		return nil
+	}
+
+	records, err := f.load()
+	if err != nil {
+		return err
+	}
+	return f.save(records)
+}
+
+func (f *jsonFile[T]) load() ([]T, error) {
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, storageError(fmt.Errorf("failed to open file %s: %w", f.path, err))
+	}
+	defer file.Close()
+
+	var records []T
+	if err := json.NewDecoder(file).Decode(&records); err != nil {
+		return nil, fmt.Errorf("failed to decode file %s: %w", f.path, err)
+	}
+	return records, nil
+}
+
+// save writes records to a temporary file and renames it over the previous
+// content, so readers never observe a partially written list.
+func (f *jsonFile[T]) save(records []T) error {
+	lines := make([]string, 0, len(records))
+	for _, record := range records {
+		b, err := json.Marshal(&record)
+		if err != nil {
+			return fmt.Errorf("failed to marshal record: %w", err)
+		}
+		lines = append(lines, string(b))
+	}
+
+	tmpPath := f.path + ".tmp"
+	content := firstLineValue + strings.Join(lines, ",\n") + lastLineValue
+	if err := os.WriteFile(tmpPath, []byte(content), filePerm); err != nil {
+		return storageError(fmt.Errorf("failed to write file %s: %w", tmpPath, err))
+	}
+
+	if err := os.Rename(tmpPath, f.path); err != nil {
+		return storageError(fmt.Errorf("failed to replace file %s: %w", f.path, err))
+	}
+	return nil
+}
+
+// update loads the records, hands them to fn and saves whatever fn returns.
+// Records are left untouched when fn fails.
+func (f *jsonFile[T]) update(fn func([]T) ([]T, error)) error {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	records, err := f.load()
+	if err != nil {
+		return err
+	}
+
+	if records, err = fn(records); err != nil {
+		return err
+	}
+
+	return f.save(records)
+}
+
+func (f *jsonFile[T]) all() ([]T, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.load()
+}
+
+// filter returns the records keep is true for. The file is decoded one record at a time,
+// so the records left out are never held together in memory.
+func (f *jsonFile[T]) filter(keep func(T) bool) ([]T, error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	file, err := os.Open(f.path)
+	if err != nil {
+		return nil, storageError(fmt.Errorf("failed to open file %s: %w", f.path, err))
+	}
+	defer file.Close()
+
+	decoder := json.NewDecoder(file)
+	if _, err := decoder.Token(); err != nil {
+		return nil, fmt.Errorf("failed to decode file %s: %w", f.path, err)
+	}
+
+	records := make([]T, 0)
+	for decoder.More() {
+		var record T
+		if err := decoder.Decode(&record); err != nil {
+			return nil, fmt.Errorf("failed to decode file %s: %w", f.path, err)
+		}
+
+		if keep(record) {
+			records = append(records, record)
+		}
+	}
+	return records, nil
+}
+
+// storageError marks err, a failure to reach the files of a store, as model.ErrUnavailable.
+func storageError(err error) error {
+	return fmt.Errorf("%w: %w", model.ErrUnavailable, err)
+}
@@ -0,0 +1,107 @@
+package repository
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go-task-tracker/model"
+	"os"
+	"slices"
+)
+
+// lineSpan is where the JSON of a task is in the task file.
+type lineSpan struct {
+	offset int64
+	length int
+}
+
+// taskIndex locates tasks in the task file: the line of each task, and the tasks found
+// under each key of the model.TaskIndexes. Tasks are only ever added to it, changes
+// that rewrite the file replace the index.
+type taskIndex struct {
+	lines map[int]lineSpan
+	keys  map[model.TaskIndex]map[string][]int
+}
+
+func newTaskIndex() *taskIndex {
+	index := &taskIndex{
+		lines: make(map[int]lineSpan),
+		keys:  make(map[model.TaskIndex]map[string][]int, len(model.TaskIndexes)),
+	}
+	for _, taskIndex := range model.TaskIndexes {
+		index.keys[taskIndex] = make(map[string][]int)
+	}
+	return index
+}
+
+func (x *taskIndex) add(task model.Task, span lineSpan) {
+	x.lines[task.Id] = span
+	for taskIndex, keys := range x.keys {
+		for _, key := range taskIndex.Keys(task) {
+			keys[key] = append(keys[key], task.Id)
+		}
+	}
+}
+
+// lookup returns the ids of the tasks selected by every lookup, in increasing order.
+func (x *taskIndex) lookup(lookups []model.TaskLookup) []int {
+	var ids map[int]bool
+	for _, lookup := range lookups {
+		found := make(map[int]bool)
+		for _, key := range lookup.Keys {
+			for _, id := range x.keys[lookup.Index][key] {
+				if ids == nil || ids[id] {
+					found[id] = true
+				}
+			}
+		}
+		ids = found
+	}
+	sorted := make([]int, 0, len(ids))
+	for id := range ids {
+		sorted = append(sorted, id)
+	}
+	slices.Sort(sorted)
+	return sorted
+}
+
+// indexTasks indexes content, a task file with one task per line. It returns false when
+// content isn't laid out that way, as when the file was edited by hand.
+func indexTasks(content []byte) (*taskIndex, bool) {
+	index := newTaskIndex()
+	lines := bytes.Split(content, []byte("\n"))
+	if len(lines) < 2 || string(lines[0])+"\n" != firstLineValue || "\n"+string(lines[len(lines)-1]) != lastLineValue {
+		return nil, false
+	}
+
+	offset := int64(len(firstLineValue))
+	for _, line := range lines[1 : len(lines)-1] {
+		// the file of no tasks has an empty line between the brackets
+		if len(line) == 0 {
+			continue
+		}
+		taskJson := bytes.TrimSuffix(line, []byte(","))
+
+		var task model.Task
+		if err := json.Unmarshal(taskJson, &task); err != nil {
+			return nil, false
+		}
+		index.add(task, lineSpan{offset: offset, length: len(taskJson)})
+		offset += int64(len(line)) + 1
+	}
+	return index, true
+}
+
+// readTask reads the task at span of the task file.
+func readTask(file *os.File, span lineSpan) (model.Task, error) {
+	b := make([]byte, span.length)
+	if _, err := file.ReadAt(b, span.offset); err != nil {
+		return model.Task{}, storageError(fmt.Errorf("failed to read task: %w", err))
+	}
+
+	var task model.Task
+	if err := json.Unmarshal(b, &task); err != nil {
+		return model.Task{}, fmt.Errorf("failed to decode task: %w", err)
+	}
+	return task, nil
+}
@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+)
+
+type UserRepositoryFile struct {
+	file       *jsonFile[model.User]
+	sequenceId int
+}
+
+func NewUserRepositoryFile(path string) (*UserRepositoryFile, error) {
+	file, err := newJSONFile[model.User](path)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := file.all()
+	if err != nil {
+		return nil, err
+	}
+
+	sequenceId := 0
+	for _, user := range users {
+		sequenceId = max(sequenceId, user.Id)
+	}
+
+	return &UserRepositoryFile{file: file, sequenceId: sequenceId}, nil
+}
+
+func (r *UserRepositoryFile) AddUser(user model.User) (model.User, error) {
+	err := r.file.update(func(users []model.User) ([]model.User, error) {
+		r.sequenceId++
+		user.Id = r.sequenceId
+		return append(users, user), nil
+	})
+	if err != nil {
+		return model.User{}, fmt.Errorf("failed to add user: %w", err)
+	}
+	return user, nil
+}
+
+func (r *UserRepositoryFile) GetAllUsers() ([]model.User, error) {
+	users, err := r.file.all()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve users: %w", err)
+	}
+	return users, nil
+}
+
+func (r *UserRepositoryFile) GetUser(id int) (model.User, error) {
+	users, err := r.GetAllUsers()
+	if err != nil {
+		return model.User{}, err
+	}
+
+	for _, user := range users {
+		if user.Id == id {
+			return user, nil
+		}
+	}
+	return model.User{}, fmt.Errorf("user with id %d: %w", id, model.ErrNotFound)
+}
@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"go-task-tracker/model"
+	"testing"
+)
+
+func Test_AddUser(t *testing.T) {
+	const fileName = "Test_AddUser.json"
+	defer removeTestFile(fileName)
+
+	r, err := NewUserRepositoryFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to create UserRepositoryFile: %s", err)
+	}
+
+	for _, name := range []string{"Ana", "Bruno"} {
+		if _, err := r.AddUser(model.User{Name: name}); err != nil {
+			t.Fatalf("failed to call AddUser: \"%v\"", err)
+		}
+	}
+
+	r, err = NewUserRepositoryFile(fileName)
+	if err != nil {
+		t.Fatalf("failed to reopen UserRepositoryFile: %s", err)
+	}
+
+	user, err := r.GetUser(2)
+	if err != nil {
+		t.Fatalf("expected GetUser call to return no errors, got \"%s\"", err)
+	}
+
+	if user.Name != "Bruno" {
+		t.Errorf("expected user 2 to be Bruno, got %s", user.Name)
+	}
+
+	if r.sequenceId != 2 {
+		t.Errorf("expected sequence id to be 2, got %d", r.sequenceId)
+	}
+}
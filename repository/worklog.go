@@ -0,0 +1,77 @@
+package repository
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+	"slices"
+)
+
+type WorkLogRepositoryFile struct {
+	file       *jsonFile[model.WorkLog]
+	sequenceId int
+}
+
+func NewWorkLogRepositoryFile(path string) (*WorkLogRepositoryFile, error) {
+	file, err := newJSONFile[model.WorkLog](path)
+	if err != nil {
+		return nil, err
+	}
+
+	workLogs, err := file.all()
+	if err != nil {
+		return nil, err
+	}
+
+	sequenceId := 0
+	for _, workLog := range workLogs {
+		sequenceId = max(sequenceId, workLog.Id)
+	}
+
+	return &WorkLogRepositoryFile{file: file, sequenceId: sequenceId}, nil
+}
+
+func (r *WorkLogRepositoryFile) AddWorkLog(workLog model.WorkLog) (model.WorkLog, error) {
+	err := r.file.update(func(workLogs []model.WorkLog) ([]model.WorkLog, error) {
+		r.sequenceId++
+		workLog.Id = r.sequenceId
+		return append(workLogs, workLog), nil
+	})
+	if err != nil {
+		return model.WorkLog{}, fmt.Errorf("failed to add work log: %w", err)
+	}
+	return workLog, nil
+}
+
+func (r *WorkLogRepositoryFile) GetAllWorkLogs() ([]model.WorkLog, error) {
+	workLogs, err := r.file.all()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve work logs: %w", err)
+	}
+	return workLogs, nil
+}
+
+func (r *WorkLogRepositoryFile) UpdateWorkLog(workLog model.WorkLog) error {
+	err := r.file.update(func(workLogs []model.WorkLog) ([]model.WorkLog, error) {
+		index := slices.IndexFunc(workLogs, func(w model.WorkLog) bool { return w.Id == workLog.Id })
+		if index == -1 {
+			return nil, fmt.Errorf("work log with id %d: %w", workLog.Id, model.ErrNotFound)
+		}
+		workLogs[index] = workLog
+		return workLogs, nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to update work log %d: %w", workLog.Id, err)
+	}
+	return nil
+}
+
+// DeleteTaskWorkLogs removes every work log of the task taskId.
+func (r *WorkLogRepositoryFile) DeleteTaskWorkLogs(taskId int) error {
+	err := r.file.update(func(workLogs []model.WorkLog) ([]model.WorkLog, error) {
+		return slices.DeleteFunc(workLogs, func(w model.WorkLog) bool { return w.TaskId == taskId }), nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete work logs of task %d: %w", taskId, err)
+	}
+	return nil
+}
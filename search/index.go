@@ -0,0 +1,215 @@
+// Package search is a full-text index of documents made of fields of text. Words are
+// matched case-insensitively, also as the start of longer words, and documents are ranked
+// with BM25.
+package search
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"sync"
+)
+
+const (
+	// k1 and b are the usual BM25 parameters: how quickly repeating a word stops adding to
+	// the score, and how much long documents are penalized.
+	k1 = 1.2
+	b  = 0.75
+
+	// prefixWeight scales the score of words matched by their start only, so exact
+	// matches rank first.
+	prefixWeight    = 0.5
+	minPrefixLength = 2
+)
+
+// Field is a piece of text of a document. Id tells apart the fields with the same Name,
+// as the comments of a task.
+type Field struct {
+	Name string
+	Id   int
+	Text string
+}
+
+type Document struct {
+	Id     int
+	Fields []Field
+}
+
+// Hit is a document matching a search, with a snippet of each of its matching fields.
+type Hit struct {
+	Id       int
+	Score    float64
+	Snippets []Snippet
+}
+
+type Index struct {
+	mutex sync.RWMutex
+	docs  map[int]Document
+	// lengths counts the words of each document, totalLength those of all documents.
+	lengths     map[int]int
+	totalLength int
+	// postings holds, for each word, how many times it is found in each document.
+	postings map[string]map[int]int
+	// terms is the sorted list of the words of postings, to find the words starting with
+	// a prefix.
+	terms []string
+}
+
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[int]Document),
+		lengths:  make(map[int]int),
+		postings: make(map[string]map[int]int),
+	}
+}
+
+// Put adds doc to the index, replacing the document with the same id.
+func (x *Index) Put(doc Document) {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	x.remove(doc.Id)
+
+	length := 0
+	for _, field := range doc.Fields {
+		for _, t := range tokenize(field.Text) {
+			docs, ok := x.postings[t.term]
+			if !ok {
+				docs = make(map[int]int)
+				x.postings[t.term] = docs
+				i, _ := slices.BinarySearch(x.terms, t.term)
+				x.terms = slices.Insert(x.terms, i, t.term)
+			}
+			docs[doc.Id]++
+			length++
+		}
+	}
+
+	x.docs[doc.Id] = doc
+	x.lengths[doc.Id] = length
+	x.totalLength += length
+}
+
+// Delete removes the document id from the index, if there.
+func (x *Index) Delete(id int) {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	x.remove(id)
+}
+
+// Reset empties the index.
+func (x *Index) Reset() {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	x.docs = make(map[int]Document)
+	x.lengths = make(map[int]int)
+	x.totalLength = 0
+	x.postings = make(map[string]map[int]int)
+	x.terms = nil
+}
+
+func (x *Index) remove(id int) {
+	doc, ok := x.docs[id]
+	if !ok {
+		return
+	}
+
+	for _, field := range doc.Fields {
+		for _, t := range tokenize(field.Text) {
+			docs := x.postings[t.term]
+			if delete(docs, id); len(docs) == 0 {
+				delete(x.postings, t.term)
+				if i, found := slices.BinarySearch(x.terms, t.term); found {
+					x.terms = slices.Delete(x.terms, i, i+1)
+				}
+			}
+		}
+	}
+
+	x.totalLength -= x.lengths[id]
+	delete(x.lengths, id)
+	delete(x.docs, id)
+}
+
+// Search returns the documents holding any word of query, best first and at most limit of
+// them. Documents holding more of the words, or holding them more often, score higher.
+func (x *Index) Search(query string, limit int) []Hit {
+	x.mutex.RLock()
+	defer x.mutex.RUnlock()
+
+	if len(x.docs) == 0 {
+		return nil
+	}
+	averageLength := float64(x.totalLength) / float64(len(x.docs))
+
+	scores := make(map[int]float64)
+	matched := make(map[int]map[string]bool)
+	for _, queryTerm := range Terms(query) {
+		// a document matching several words starting with queryTerm counts its best one
+		best := make(map[int]float64)
+		for _, term := range x.expand(queryTerm) {
+			weight := 1.0
+			if term != queryTerm {
+				weight = prefixWeight
+			}
+
+			docs := x.postings[term]
+			idf := math.Log(1 + (float64(len(x.docs)-len(docs))+0.5)/(float64(len(docs))+0.5))
+			for id, frequency := range docs {
+				tf := float64(frequency)
+				norm := k1 * (1 - b + b*float64(x.lengths[id])/averageLength)
+				best[id] = max(best[id], weight*idf*tf*(k1+1)/(tf+norm))
+
+				if matched[id] == nil {
+					matched[id] = make(map[string]bool)
+				}
+				matched[id][term] = true
+			}
+		}
+
+		for id, score := range best {
+			scores[id] += score
+		}
+	}
+
+	hits := make([]Hit, 0, len(scores))
+	for id, score := range scores {
+		hits = append(hits, Hit{Id: id, Score: score})
+	}
+	slices.SortFunc(hits, func(a, b Hit) int {
+		if a.Score != b.Score {
+			if a.Score > b.Score {
+				return -1
+			}
+			return 1
+		}
+		return a.Id - b.Id
+	})
+
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	for i := range hits {
+		hits[i].Snippets = snippets(x.docs[hits[i].Id], matched[hits[i].Id])
+	}
+	return hits
+}
+
+// expand returns the indexed words matching queryTerm, itself and the words it starts.
+func (x *Index) expand(queryTerm string) []string {
+	if !isPrefixTerm(queryTerm) {
+		if _, ok := x.postings[queryTerm]; ok {
+			return []string{queryTerm}
+		}
+		return nil
+	}
+
+	i, _ := slices.BinarySearch(x.terms, queryTerm)
+	end := i
+	for end < len(x.terms) && strings.HasPrefix(x.terms[end], queryTerm) {
+		end++
+	}
+	return x.terms[i:end]
+}
@@ -0,0 +1,114 @@
+package search
+
+import (
+	"fmt"
+	"slices"
+	"testing"
+)
+
+func newTestIndex() *Index {
+	index := NewIndex()
+	index.Put(Document{Id: 1, Fields: []Field{{Name: "description", Text: "Fix the flaky login test"}}})
+	index.Put(Document{Id: 2, Fields: []Field{
+		{Name: "description", Text: "Write the docs"},
+		{Name: "comment", Id: 7, Text: "The login page needs docs too, login is confusing"},
+	}})
+	index.Put(Document{Id: 3, Fields: []Field{{Name: "description", Text: "Logging is too verbose"}}})
+	index.Put(Document{Id: 4, Fields: []Field{{Name: "description", Text: "Release 2.0"}}})
+	return index
+}
+
+func TestIndex_Search(t *testing.T) {
+	index := newTestIndex()
+
+	var testTable = []struct {
+		query    string
+		expected []int
+	}{
+		{"flaky", []int{1}},
+		{"FLAKY Test", []int{1}},
+		{"login", []int{1, 2}},
+		{"log", []int{3, 1, 2}},
+		{"docs login", []int{2, 1}},
+		{"logging", []int{3}},
+		{"2", []int{4}},
+		{"l", nil},
+		{"missing", nil},
+		{"", nil},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%s), Expect: %v", testData.query, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			var ids []int
+			for _, hit := range index.Search(testData.query, 0) {
+				ids = append(ids, hit.Id)
+			}
+
+			if !slices.Equal(ids, testData.expected) {
+				t.Errorf("with input (%s) got documents %v, but expected %v", testData.query, ids, testData.expected)
+			}
+		})
+	}
+}
+
+func TestIndex_PutAndDelete(t *testing.T) {
+	index := newTestIndex()
+
+	index.Put(Document{Id: 1, Fields: []Field{{Name: "description", Text: "Fix the stable test"}}})
+	if hits := index.Search("flaky", 0); len(hits) != 0 {
+		t.Errorf("expected replaced document to be found no more, got %v", hits)
+	}
+	if hits := index.Search("stable", 0); len(hits) != 1 || hits[0].Id != 1 {
+		t.Errorf("expected replaced document to be found by its new text, got %v", hits)
+	}
+
+	index.Delete(3)
+	if hits := index.Search("logging", 0); len(hits) != 0 {
+		t.Errorf("expected deleted document to be found no more, got %v", hits)
+	}
+	if slices.Contains(index.terms, "verbose") {
+		t.Errorf("expected words of deleted document to leave the index, got %v", index.terms)
+	}
+
+	if hits := index.Search("login", 1); len(hits) != 1 || hits[0].Id != 2 {
+		t.Errorf("expected limit to keep the best hit, got %v", hits)
+	}
+}
+
+func TestIndex_Search_Snippets(t *testing.T) {
+	index := newTestIndex()
+	index.Put(Document{Id: 5, Fields: []Field{{Name: "description", Text: "one two three four five six seven eight nine ten " +
+		"eleven twelve thirteen fourteen fifteen sixteen seventeen <b>target</b> eighteen"}}})
+
+	var testTable = []struct {
+		query    string
+		expected []Snippet
+	}{
+		{"flaky", []Snippet{{Name: "description", Text: "Fix the <mark>flaky</mark> login test"}}},
+		{"login", []Snippet{{Name: "comment", Id: 7, Text: "The <mark>login</mark> page needs docs too, <mark>login</mark> is confusing"}}},
+		{"docs", []Snippet{
+			{Name: "description", Text: "Write the <mark>docs</mark>"},
+			{Name: "comment", Id: 7, Text: "The login page needs <mark>docs</mark> too, login is confusing"},
+		}},
+		{"target", []Snippet{{Name: "description", Text: "…six seven eight nine ten eleven twelve thirteen fourteen fifteen sixteen seventeen &lt;b&gt;<mark>target</mark>&lt;/b&gt; eighteen"}}},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%s), Expect: %v", testData.query, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			hits := index.Search(testData.query, 1)
+			if len(hits) != 1 {
+				t.Fatalf("expected a hit, got %v", hits)
+			}
+
+			if !slices.Equal(hits[0].Snippets, testData.expected) {
+				t.Errorf("with input (%s) got snippets %q, but expected %q", testData.query, hits[0].Snippets, testData.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,100 @@
+package search
+
+import (
+	"html"
+	"strings"
+)
+
+const (
+	// snippetWords is how many words a snippet shows around the matches.
+	snippetWords = 16
+	maxSnippets  = 3
+)
+
+// Snippet is an excerpt of a matching field. Text is HTML-escaped, with the matching
+// words wrapped in <mark> and an ellipsis where the field was cut.
+type Snippet struct {
+	Name string
+	Id   int
+	Text string
+}
+
+// snippets returns the excerpts of the first fields of doc holding the terms.
+func snippets(doc Document, terms map[string]bool) []Snippet {
+	var result []Snippet
+	for _, field := range doc.Fields {
+		if len(result) == maxSnippets {
+			break
+		}
+
+		if text, ok := excerpt(field.Text, terms); ok {
+			result = append(result, Snippet{Name: field.Name, Id: field.Id, Text: text})
+		}
+	}
+	return result
+}
+
+// excerpt cuts text to the snippetWords words holding the most of terms, returning false
+// when text holds none.
+func excerpt(text string, terms map[string]bool) (string, bool) {
+	tokens := tokenize(text)
+
+	// slide a window of snippetWords words over the text, keeping the first that holds
+	// the most matches
+	first, best, count := 0, 0, 0
+	for i, t := range tokens {
+		if terms[t.term] {
+			count++
+		}
+		if i >= snippetWords && terms[tokens[i-snippetWords].term] {
+			count--
+		}
+		if count > best {
+			first, best = max(0, i-snippetWords+1), count
+		}
+	}
+
+	if best == 0 {
+		return "", false
+	}
+
+	// center the window on its matches
+	last := min(first+snippetWords, len(tokens)) - 1
+	for !terms[tokens[first].term] {
+		first++
+	}
+	for !terms[tokens[last].term] {
+		last--
+	}
+	first = max(0, min(first-(snippetWords-(last-first+1))/2, len(tokens)-snippetWords))
+	window := tokens[first:min(first+snippetWords, len(tokens))]
+
+	start, end := 0, len(text)
+	if first > 0 {
+		start = window[0].start
+	}
+	if first+len(window) < len(tokens) {
+		end = window[len(window)-1].end
+	}
+
+	var snippet strings.Builder
+	if start > 0 {
+		snippet.WriteString("…")
+	}
+
+	offset := start
+	for _, t := range window {
+		if !terms[t.term] {
+			continue
+		}
+		snippet.WriteString(html.EscapeString(text[offset:t.start]))
+		snippet.WriteString("<mark>" + html.EscapeString(text[t.start:t.end]) + "</mark>")
+		offset = t.end
+	}
+	snippet.WriteString(html.EscapeString(text[offset:end]))
+
+	if end < len(text) {
+		snippet.WriteString("…")
+	}
+	return strings.Join(strings.Fields(snippet.String()), " "), true
+}
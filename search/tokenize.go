@@ -0,0 +1,55 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// token is a word of a text, start and end being its byte offsets in the text.
+type token struct {
+	term  string
+	start int
+	end   int
+}
+
+// tokenize splits text into words, runs of letters and digits, and lowercases them.
+func tokenize(text string) []token {
+	var tokens []token
+	start := -1
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case inWord && start == -1:
+			start = i
+		case !inWord && start != -1:
+			tokens = append(tokens, token{term: strings.ToLower(text[start:i]), start: start, end: i})
+			start = -1
+		}
+	}
+
+	if start != -1 {
+		tokens = append(tokens, token{term: strings.ToLower(text[start:]), start: start, end: len(text)})
+	}
+	return tokens
+}
+
+// Terms returns the distinct words of text as the index sees them, lowercased and in
+// order of appearance.
+func Terms(text string) []string {
+	var terms []string
+	seen := make(map[string]bool)
+	for _, t := range tokenize(text) {
+		if !seen[t.term] {
+			seen[t.term] = true
+			terms = append(terms, t.term)
+		}
+	}
+	return terms
+}
+
+// isPrefixTerm tells whether term is long enough to also match the words it starts, single
+// letters would match most of the index.
+func isPrefixTerm(term string) bool {
+	return utf8.RuneCountInString(term) >= minPrefixLength
+}
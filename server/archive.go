@@ -0,0 +1,55 @@
+package server
+
+import (
+	"net/http"
+)
+
+// HandleGetArchivedTasks lists archived tasks with the filters, sort, pages and views of
+// task listings.
+// The from and to query params limit them to tasks completed between the two dates.
+func (h TaskHandler) HandleGetArchivedTasks(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	filter, ok := h.taskFilter(w, r)
+	if !ok {
+		return
+	}
+
+	sort, ok := h.taskSort(w, r)
+	if !ok {
+		return
+	}
+
+	page, ok := h.taskPage(w, r)
+	if !ok {
+		return
+	}
+
+	view, ok := h.taskView(w, r)
+	if !ok {
+		return
+	}
+
+	from, ok := queryDate(w, r, &h.log, "from")
+	if !ok {
+		return
+	}
+
+	to, ok := queryDate(w, r, &h.log, "to")
+	if !ok {
+		return
+	}
+
+	if !to.IsZero() {
+		// to is included, so tasks completed during that day are listed
+		to = to.AddDate(0, 0, 1)
+	}
+
+	tasks, err := h.service.GetArchivedTasks(filter, sort, page, from, to)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	h.writeTaskPage(w, r, tasks, view)
+}
@@ -0,0 +1,133 @@
+package server
+
+import (
+	"bufio"
+	"fmt"
+	"log/slog"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"time"
+)
+
+const attachmentFormField = "file"
+
+// HandlePostAttachment stores the part named file of a multipart/form-data body as an
+// attachment of the task. The part is streamed to the blob store, never held in memory.
+func (h TaskHandler) HandlePostAttachment(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	taskId, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	reader, err := r.MultipartReader()
+	if err != nil {
+		h.log.Info("attachment upload is not a multipart request", slog.Any("err", err))
+		writeProblem(w, r, &h.log, http.StatusBadRequest, "attachment upload must be a multipart request")
+		return
+	}
+
+	for {
+		part, err := reader.NextPart()
+		if err != nil {
+			h.log.Info(fmt.Sprintf("multipart request has no %s part", attachmentFormField), slog.Any("err", err))
+			writeProblem(w, r, &h.log, http.StatusBadRequest, fmt.Sprintf("multipart request has no %s part", attachmentFormField))
+			return
+		}
+
+		if part.FormName() != attachmentFormField {
+			part.Close()
+			continue
+		}
+
+		filename := filepath.Base(part.FileName())
+		content := bufio.NewReader(part)
+		contentType := detectContentType(content)
+
+		uploader, _ := currentUser(r)
+		attachment, err := h.service.AddAttachment(taskId, uploader.Id, filename, contentType, content)
+		part.Close()
+		if err != nil {
+			writeError(w, r, &h.log, err)
+			return
+		}
+
+		w.Header().Set("Location", fmt.Sprintf("/tasks/%d/attachments/%d", taskId, attachment.Id))
+		writeJSON(w, &h.log, http.StatusCreated, &attachment)
+		return
+	}
+}
+
+func (h TaskHandler) HandleGetAttachments(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	taskId, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	attachments, err := h.service.GetAttachments(taskId)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusOK, &attachments)
+}
+
+// HandleDownloadAttachment serves the content of an attachment, honoring Range requests.
+func (h TaskHandler) HandleDownloadAttachment(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	taskId, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	attachmentId, ok := pathInt(w, r, &h.log, "attachmentId")
+	if !ok {
+		return
+	}
+
+	attachment, content, err := h.service.OpenAttachment(taskId, attachmentId)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+	defer content.Close()
+
+	w.Header().Set("Content-Type", attachment.ContentType)
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": attachment.Filename}))
+	w.Header().Set("ETag", fmt.Sprintf(`"%s"`, attachment.Hash))
+	http.ServeContent(w, r, attachment.Filename, time.Time(attachment.CreatedAt), content)
+}
+
+func (h TaskHandler) HandleDeleteAttachment(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	taskId, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	attachmentId, ok := pathInt(w, r, &h.log, "attachmentId")
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteAttachment(taskId, attachmentId); err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// detectContentType sniffs the media type of an upload from the first bytes of content,
+// the type sent by the client isn't trusted.
+func detectContentType(content *bufio.Reader) string {
+	head, _ := content.Peek(512)
+	return http.DetectContentType(head)
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"context"
+	"go-task-tracker/model"
+	"go-task-tracker/service"
+	"log/slog"
+	"net/http"
+	"strings"
+)
+
+type userContextKey struct{}
+
+// Authenticate resolves the bearer token of each request to a user and stores it in
+// the request context. Requests without an Authorization header are passed on anonymously,
+// requests with an unknown token are rejected.
+func Authenticate(users service.UserService, log *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		if header == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		token, found := strings.CutPrefix(header, "Bearer ")
+		if !found {
+			log.Info("authorization header without bearer token")
+			writeProblem(w, r, log, http.StatusUnauthorized, "authorization header without bearer token")
+			return
+		}
+
+		user, err := users.Authenticate(token)
+		if err != nil {
+			log.Info("failed to authenticate request", slog.Any("err", err))
+			writeProblem(w, r, log, http.StatusUnauthorized, "invalid token")
+			return
+		}
+
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), userContextKey{}, user)))
+	})
+}
+
+// currentUser returns the user authenticated for r, if any.
+func currentUser(r *http.Request) (model.User, bool) {
+	user, ok := r.Context().Value(userContextKey{}).(model.User)
+	return user, ok
+}
@@ -0,0 +1,105 @@
+package server
+
+import (
+	"go-task-tracker/model"
+	"net/http"
+)
+
+func (h TaskHandler) HandlePostChecklistItem(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	taskId, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	var item model.CreateChecklistItem
+	if !decodeJSON(w, r, &h.log, &item) {
+		return
+	}
+
+	actor, _ := currentUser(r)
+	created, err := h.service.AddChecklistItem(taskId, item, actor.Id)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusCreated, &created)
+}
+
+func (h TaskHandler) HandleReorderChecklist(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	taskId, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	var order model.ReorderChecklist
+	if !decodeJSON(w, r, &h.log, &order) {
+		return
+	}
+
+	actor, _ := currentUser(r)
+	checklist, err := h.service.ReorderChecklist(taskId, order.Ids, actor.Id)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusOK, &checklist)
+}
+
+func (h TaskHandler) HandleCheckChecklistItem(w http.ResponseWriter, r *http.Request) {
+	h.setChecklistItem(w, r, true)
+}
+
+func (h TaskHandler) HandleUncheckChecklistItem(w http.ResponseWriter, r *http.Request) {
+	h.setChecklistItem(w, r, false)
+}
+
+func (h TaskHandler) setChecklistItem(w http.ResponseWriter, r *http.Request, checked bool) {
+	defer r.Body.Close()
+
+	taskId, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	itemId, ok := pathInt(w, r, &h.log, "itemId")
+	if !ok {
+		return
+	}
+
+	actor, _ := currentUser(r)
+	item, err := h.service.CheckChecklistItem(taskId, itemId, checked, actor.Id)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusOK, &item)
+}
+
+func (h TaskHandler) HandleDeleteChecklistItem(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	taskId, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	itemId, ok := pathInt(w, r, &h.log, "itemId")
+	if !ok {
+		return
+	}
+
+	actor, _ := currentUser(r)
+	if err := h.service.DeleteChecklistItem(taskId, itemId, actor.Id); err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"go-task-tracker/model"
+	"net/http"
+)
+
+func (h TaskHandler) HandlePostComment(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	taskId, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	author, ok := currentUser(r)
+	if !ok {
+		h.log.Info("commenting requires an authenticated user")
+		writeProblem(w, r, &h.log, http.StatusUnauthorized, "commenting requires an authenticated user")
+		return
+	}
+
+	var comment model.CreateComment
+	if !decodeJSON(w, r, &h.log, &comment) {
+		return
+	}
+
+	created, err := h.service.AddComment(taskId, author.Id, comment)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusCreated, &created)
+}
+
+func (h TaskHandler) HandleGetComments(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	taskId, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	comments, err := h.service.GetComments(taskId)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusOK, &comments)
+}
+
+func (h TaskHandler) HandleUpdateComment(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	taskId, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	commentId, ok := pathInt(w, r, &h.log, "commentId")
+	if !ok {
+		return
+	}
+
+	author, ok := currentUser(r)
+	if !ok {
+		h.log.Info("editing a comment requires an authenticated user")
+		writeProblem(w, r, &h.log, http.StatusUnauthorized, "editing a comment requires an authenticated user")
+		return
+	}
+
+	var comment model.UpdateComment
+	if !decodeJSON(w, r, &h.log, &comment) {
+		return
+	}
+
+	updated, err := h.service.UpdateComment(taskId, commentId, author.Id, comment)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusOK, &updated)
+}
+
+func (h TaskHandler) HandleDeleteComment(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	taskId, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	commentId, ok := pathInt(w, r, &h.log, "commentId")
+	if !ok {
+		return
+	}
+
+	author, ok := currentUser(r)
+	if !ok {
+		h.log.Info("deleting a comment requires an authenticated user")
+		writeProblem(w, r, &h.log, http.StatusUnauthorized, "deleting a comment requires an authenticated user")
+		return
+	}
+
+	if err := h.service.DeleteComment(taskId, commentId, author.Id); err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,24 @@
+package server
+
+import (
+	"net/http"
+)
+
+// HandleGetEstimates sums task estimates, optionally grouped with group_by=status|tag and
+// narrowed with the filters of GET /tasks.
+func (h TaskHandler) HandleGetEstimates(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	filter, ok := h.taskFilter(w, r)
+	if !ok {
+		return
+	}
+
+	totals, err := h.service.GetEstimateTotals(filter, r.URL.Query().Get("group_by"))
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusOK, &totals)
+}
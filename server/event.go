@@ -0,0 +1,99 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-task-tracker/model"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+// eventKeepAlive is how often an idle event stream sends a comment, so proxies keep the
+// connection open and disconnected clients are noticed.
+const eventKeepAlive = 15 * time.Second
+
+// HandleGetEvents streams the created, updated and deleted events of the tasks matching
+// the filters of GET /tasks as server-sent events. Clients resuming with a Last-Event-ID
+// header first get the events they missed, or a reset event when those are no longer
+// kept, after which they should read the tasks again.
+func (h TaskHandler) HandleGetEvents(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	filter, ok := h.taskFilter(w, r)
+	if !ok {
+		return
+	}
+
+	subscription, err := h.service.SubscribeEvents(filter, r.Header.Get("Last-Event-ID"))
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+	defer subscription.Close()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+
+	controller := http.NewResponseController(w)
+	if subscription.Missed {
+		if _, err := fmt.Fprintf(w, "id: %s\nevent: reset\ndata: {}\n\n", subscription.LastEventId); err != nil {
+			h.log.Error(fmt.Sprintf("error when writing http response: %s", err))
+			return
+		}
+	}
+
+	for _, event := range subscription.Replay {
+		if !h.writeEvent(w, event) {
+			return
+		}
+	}
+
+	if err := controller.Flush(); err != nil {
+		h.log.Error(fmt.Sprintf("failed to flush event stream: %s", err))
+		return
+	}
+
+	keepAlive := time.NewTicker(eventKeepAlive)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event, ok := <-subscription.Events:
+			// a closed subscription fell behind, the client reconnects and resumes
+			if !ok || !h.writeEvent(w, event) {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+
+		if err := controller.Flush(); err != nil {
+			return
+		}
+	}
+}
+
+// writeEvent writes event to the stream w, with its timestamps in the time zone of the
+// request.
+func (h TaskHandler) writeEvent(w http.ResponseWriter, event model.TaskEvent) bool {
+	if localized, ok := w.(localizedWriter); ok {
+		event = localize(reflect.ValueOf(event), localized.location).Interface().(model.TaskEvent)
+	}
+
+	data, err := json.Marshal(&event)
+	if err != nil {
+		h.log.Error(fmt.Sprintf("failed to marshal json: %s", err))
+		return false
+	}
+
+	if _, err := fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", event.Id, event.Type, data); err != nil {
+		h.log.Error(fmt.Sprintf("error when writing http response: %s", err))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"go-task-tracker/model"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWriteEvent_SubscribersInOtherZones(t *testing.T) {
+	due := model.DateTime(time.Date(2026, 3, 2, 9, 0, 0, 0, time.UTC))
+	// the broker hands the same event, and so the same pointers, to every subscriber
+	event := model.TaskEvent{Id: "1", Type: model.TaskUpdated, Task: model.Task{Id: 1, DueAt: &due}, At: due}
+	h := TaskHandler{log: *slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	var testTable = []struct {
+		zone     string
+		expected string
+	}{
+		{"Asia/Tokyo", `"DueAt":"2026-03-02T18:00:00+09:00"`},
+		{"America/New_York", `"DueAt":"2026-03-02T04:00:00-05:00"`},
+	}
+
+	recorders := make([]*httptest.ResponseRecorder, len(testTable))
+	var wg sync.WaitGroup
+	for i, testData := range testTable {
+		location, err := time.LoadLocation(testData.zone)
+		if err != nil {
+			t.Fatalf("failed to load time zone %s: %s", testData.zone, err)
+		}
+
+		recorders[i] = httptest.NewRecorder()
+		wg.Add(1)
+		go func(w http.ResponseWriter) {
+			defer wg.Done()
+			h.writeEvent(w, event)
+		}(localizedWriter{ResponseWriter: recorders[i], location: location})
+	}
+	wg.Wait()
+
+	for i, testData := range testTable {
+		if body := recorders[i].Body.String(); !strings.Contains(body, testData.expected) {
+			t.Errorf("with zone %s got %q, but expected it to hold %s", testData.zone, body, testData.expected)
+		}
+	}
+
+	if location := time.Time(due).Location(); location != time.UTC {
+		t.Errorf("expected the shared due date to stay in UTC, got %s", location)
+	}
+}
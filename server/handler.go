@@ -1,15 +1,25 @@
 package server
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"go-task-tracker/model"
 	"go-task-tracker/service"
+	"io"
 	"log/slog"
+	"mime"
 	"net/http"
+	"reflect"
 	"strconv"
+	"strings"
+	"time"
 )
 
+// mergePatchType is the media type of JSON merge patches.
+const mergePatchType = "application/merge-patch+json"
+
 type TaskHandler struct {
 	service service.TaskService
 	log     slog.Logger
@@ -22,8 +32,42 @@ func NewTaskHandler(service service.TaskService, log *slog.Logger) TaskHandler {
 	}
 	http.HandleFunc("POST /tasks", h.HandlePostTask)
 	http.HandleFunc("GET /tasks", h.HandleGetTasks)
+	http.HandleFunc("GET /tasks/{id}", h.HandleGetTask)
 	http.HandleFunc("PUT /tasks/{id}", h.HandleUpdateTask)
+	http.HandleFunc("PATCH /tasks/{id}", h.HandlePatchTask)
 	http.HandleFunc("DELETE /tasks/{id}", h.HandleDeleteTask)
+	http.HandleFunc("GET /users/{id}/tasks", h.HandleGetUserTasks)
+	http.HandleFunc("POST /tasks/{id}/comments", h.HandlePostComment)
+	http.HandleFunc("GET /tasks/{id}/comments", h.HandleGetComments)
+	http.HandleFunc("PUT /tasks/{id}/comments/{commentId}", h.HandleUpdateComment)
+	http.HandleFunc("DELETE /tasks/{id}/comments/{commentId}", h.HandleDeleteComment)
+	http.HandleFunc("POST /tasks/{id}/attachments", h.HandlePostAttachment)
+	http.HandleFunc("GET /tasks/{id}/attachments", h.HandleGetAttachments)
+	http.HandleFunc("GET /tasks/{id}/attachments/{attachmentId}", h.HandleDownloadAttachment)
+	http.HandleFunc("DELETE /tasks/{id}/attachments/{attachmentId}", h.HandleDeleteAttachment)
+	http.HandleFunc("POST /tasks/{id}/timer/start", h.HandleStartTimer)
+	http.HandleFunc("POST /tasks/{id}/timer/stop", h.HandleStopTimer)
+	http.HandleFunc("POST /tasks/{id}/worklogs", h.HandlePostWorkLog)
+	http.HandleFunc("GET /tasks/{id}/worklogs", h.HandleGetWorkLogs)
+	http.HandleFunc("GET /worklogs/totals", h.HandleGetWorkLogTotals)
+	http.HandleFunc("GET /estimates", h.HandleGetEstimates)
+	http.HandleFunc("GET /projects/{key}/tasks", h.HandleGetProjectTasks)
+	http.HandleFunc("POST /tasks/{id}/move", h.HandleMoveTask)
+	http.HandleFunc("GET /tasks/{id}/history", h.HandleGetHistory)
+	http.HandleFunc("POST /tasks/{id}/checklist", h.HandlePostChecklistItem)
+	http.HandleFunc("PUT /tasks/{id}/checklist/order", h.HandleReorderChecklist)
+	http.HandleFunc("POST /tasks/{id}/checklist/{itemId}/check", h.HandleCheckChecklistItem)
+	http.HandleFunc("POST /tasks/{id}/checklist/{itemId}/uncheck", h.HandleUncheckChecklistItem)
+	http.HandleFunc("DELETE /tasks/{id}/checklist/{itemId}", h.HandleDeleteChecklistItem)
+	http.HandleFunc("POST /tasks/{id}/links", h.HandlePostLink)
+	http.HandleFunc("DELETE /tasks/{id}/links", h.HandleDeleteLink)
+	http.HandleFunc("POST /tasks/{id}/duplicate", h.HandleResolveDuplicate)
+	http.HandleFunc("GET /archive/tasks", h.HandleGetArchivedTasks)
+	http.HandleFunc("GET /search", h.HandleSearch)
+	http.HandleFunc("GET /stats", h.HandleGetStats)
+	http.HandleFunc("GET /reports/burndown", h.HandleGetBurndown)
+	http.HandleFunc("GET /reports/cumulative-flow", h.HandleGetCumulativeFlow)
+	http.HandleFunc("GET /events", h.HandleGetEvents)
 	return h
 }
 
@@ -31,96 +75,466 @@ func (h TaskHandler) HandlePostTask(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
 	var task model.CreateTask
-	decoder := json.NewDecoder(r.Body)
-
-	if err := decoder.Decode(&task); err != nil {
-		h.log.Error("failed to process request: ", err)
-		w.WriteHeader(http.StatusBadRequest)
+	if !decodeJSON(w, r, &h.log, &task) {
 		return
 	}
 
-	if err := h.service.AddTask(task); err != nil {
-		h.log.Error("failed to process request: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
+	reporter, _ := currentUser(r)
+	created, err := h.service.AddTask(task, reporter.Id)
+	if err != nil {
+		writeError(w, r, &h.log, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusCreated)
+	w.Header().Set("Location", fmt.Sprintf("/tasks/%d", created.Id))
+	writeJSON(w, &h.log, http.StatusCreated, &created)
 }
 
 func (h TaskHandler) HandleGetTasks(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
+	filter, ok := h.taskFilter(w, r)
+	if !ok {
+		return
+	}
+
+	sort, ok := h.taskSort(w, r)
+	if !ok {
+		return
+	}
+
+	h.writeTasks(w, r, filter, sort)
+}
+
+// taskFilter reads the filters of a task listing from the query params of r, replying
+// with an error when any is invalid.
+func (h TaskHandler) taskFilter(w http.ResponseWriter, r *http.Request) (model.TaskFilter, bool) {
 	statusFilter := r.URL.Query().Get("status")
-	description := r.URL.Query().Get("description")
+	assigneeFilter := r.URL.Query().Get("assignee")
+	filter := model.TaskFilter{
+		Description: r.URL.Query().Get("description"),
+		Text:        r.URL.Query().Get("text"),
+		Tag:         r.URL.Query().Get("tag"),
+		Project:     r.URL.Query().Get("project"),
+		Query:       r.URL.Query().Get("q"),
+		Location:    requestLocation(r),
+	}
+
+	if user, ok := currentUser(r); ok {
+		filter.UserId = user.Id
+	}
+
+	var ok bool
+	if filter.SprintId, ok = queryInt(w, r, &h.log, "sprint"); !ok {
+		return model.TaskFilter{}, false
+	}
 
-	status := -1
 	if statusFilter != "" {
-		var err error
-		if status, err = strconv.Atoi(statusFilter); err != nil {
+		status, err := model.ParseTaskStatus(statusFilter)
+		if err != nil {
 			h.log.Info(fmt.Sprintf("input %s is invalid for query param status", statusFilter))
-			w.WriteHeader(http.StatusBadRequest)
+			writeProblem(w, r, &h.log, http.StatusBadRequest, fmt.Sprintf("input %s is invalid for query param status", statusFilter))
+			return model.TaskFilter{}, false
 		}
+		filter.Status = &status
 	}
 
-	response, err := h.service.GetTasks(model.TaskStatus(status), description)
+	switch assigneeFilter {
+	case "":
+	case "me":
+		user, ok := currentUser(r)
+		if !ok {
+			h.log.Info("query param assignee=me requires an authenticated user")
+			writeProblem(w, r, &h.log, http.StatusUnauthorized, "query param assignee=me requires an authenticated user")
+			return model.TaskFilter{}, false
+		}
+		filter.AssigneeId = user.Id
+	default:
+		var err error
+		if filter.AssigneeId, err = strconv.Atoi(assigneeFilter); err != nil {
+			h.log.Info(fmt.Sprintf("input %s is invalid for query param assignee", assigneeFilter))
+			writeProblem(w, r, &h.log, http.StatusBadRequest, fmt.Sprintf("input %s is invalid for query param assignee", assigneeFilter))
+			return model.TaskFilter{}, false
+		}
+	}
+
+	// custom fields are filtered with params such as cf.severity=high
+	for param, values := range r.URL.Query() {
+		if name, ok := strings.CutPrefix(param, "cf."); ok {
+			if filter.CustomFields == nil {
+				filter.CustomFields = make(map[string]string)
+			}
+			filter.CustomFields[name] = values[0]
+		}
+	}
+
+	return filter, true
+}
+
+// taskSort reads the sort query param of a task listing, such as sort=status,-cf.severity.
+func (h TaskHandler) taskSort(w http.ResponseWriter, r *http.Request) (model.TaskSort, bool) {
+	value := r.URL.Query().Get("sort")
+	if value == "" {
+		return model.TaskSort{}, true
+	}
+
+	sort, err := model.ParseTaskSort(value)
 	if err != nil {
-		h.log.Error(fmt.Sprintf("failed to get tasks: %s", err))
-		w.WriteHeader(http.StatusInternalServerError)
+		h.log.Info(fmt.Sprintf("input %s is invalid for query param sort: %s", value, err))
+		writeProblem(w, r, &h.log, http.StatusBadRequest, fmt.Sprintf("input %s is invalid for query param sort: %s", value, err))
+		return model.TaskSort{}, false
 	}
+	return sort, true
+}
+
+func (h TaskHandler) HandleGetUserTasks(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
 
-	jsonRes, err := json.Marshal(&response)
+	id, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	sort, ok := h.taskSort(w, r)
+	if !ok {
+		return
+	}
+
+	page, ok := h.taskPage(w, r)
+	if !ok {
+		return
+	}
+
+	view, ok := h.taskView(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.service.GetUserTasks(id, sort, page)
 	if err != nil {
-		h.log.Error(fmt.Sprintf("failed to marshal json: %s", err))
-		w.WriteHeader(http.StatusInternalServerError)
+		writeError(w, r, &h.log, err)
+		return
 	}
 
-	w.Header().Set("Content-Type", "application/json")
-	if _, err := w.Write(jsonRes); err != nil {
-		h.log.Error(fmt.Sprintf("error when writing http response: %s", err))
+	h.writeTaskPage(w, r, response, view)
+}
+
+func (h TaskHandler) HandleGetProjectTasks(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	filter, ok := h.taskFilter(w, r)
+	if !ok {
+		return
+	}
+
+	sort, ok := h.taskSort(w, r)
+	if !ok {
+		return
+	}
+
+	filter.Project = r.PathValue("key")
+	h.writeTasks(w, r, filter, sort)
+}
+
+func (h TaskHandler) HandleMoveTask(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	id, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	var move model.MoveTask
+	if !decodeJSON(w, r, &h.log, &move) {
+		return
+	}
+
+	actor, _ := currentUser(r)
+	task, err := h.service.MoveTask(id, move.Project, actor.Id)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
 	}
+
+	writeJSON(w, &h.log, http.StatusOK, &task)
 }
 
+func (h TaskHandler) HandleGetHistory(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	id, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	history, err := h.service.GetHistory(id)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusOK, &history)
+}
+
+func (h TaskHandler) writeTasks(w http.ResponseWriter, r *http.Request, filter model.TaskFilter, sort model.TaskSort) {
+	page, ok := h.taskPage(w, r)
+	if !ok {
+		return
+	}
+
+	view, ok := h.taskView(w, r)
+	if !ok {
+		return
+	}
+
+	response, err := h.service.GetTasks(filter, sort, page)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	h.writeTaskPage(w, r, response, view)
+}
+
+func (h TaskHandler) HandleGetTask(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	id, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	view, ok := h.taskView(w, r)
+	if !ok {
+		return
+	}
+
+	task, err := h.service.GetTask(id)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	if view.fields == nil && view.include == nil {
+		writeJSON(w, &h.log, http.StatusOK, &task)
+		return
+	}
+
+	shaped, ok := h.shapeTasks(w, r, view, []model.Task{task}, []any{&task})
+	if !ok {
+		return
+	}
+	writeJSON(w, &h.log, http.StatusOK, &shaped[0])
+}
+
+// HandleUpdateTask replaces the task with the body, the fields the body leaves out are
+// cleared.
 func (h TaskHandler) HandleUpdateTask(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
-	id, err := strconv.Atoi(r.PathValue("id"))
+	id, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	var task model.ReplaceTask
+	if !decodeJSON(w, r, &h.log, &task) {
+		return
+	}
+
+	actor, _ := currentUser(r)
+	updated, err := h.service.ReplaceTask(id, task, actor.Id)
 	if err != nil {
-		h.log.Error(fmt.Sprintf("invalid path variable id with value %s", r.PathValue("id")))
-		w.WriteHeader(http.StatusBadRequest)
+		writeError(w, r, &h.log, err)
+		return
 	}
 
-	var task model.UpdateTask
-	decoder := json.NewDecoder(r.Body)
+	writeJSON(w, &h.log, http.StatusOK, &updated)
+}
+
+// HandlePatchTask changes the task with the JSON merge patch (RFC 7396) in the body. The
+// patch applies to the fields of model.ReplaceTask, null clears a field.
+func (h TaskHandler) HandlePatchTask(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
 
-	if err := decoder.Decode(&task); err != nil {
-		h.log.Error("failed to process request: ", err)
-		w.WriteHeader(http.StatusBadRequest)
+	id, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
 		return
 	}
 
-	if err := h.service.UpdateTask(id, task); err != nil {
-		h.log.Error("failed to process request: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
+	if contentType := r.Header.Get("Content-Type"); contentType != "" {
+		mediaType, _, err := mime.ParseMediaType(contentType)
+		if err != nil || (mediaType != mergePatchType && mediaType != "application/json") {
+			h.log.Info(fmt.Sprintf("unsupported content type %s for a merge patch", contentType))
+			writeProblem(w, r, &h.log, http.StatusUnsupportedMediaType, fmt.Sprintf("patches must be sent as %s", mergePatchType))
+			return
+		}
+	}
+
+	patch, err := io.ReadAll(r.Body)
+	if err != nil {
+		h.log.Info("failed to read request body", slog.Any("err", err))
+		writeProblem(w, r, &h.log, http.StatusBadRequest, "failed to read request body")
+		return
+	}
+
+	// the patch is merged into the task under its lock, so concurrent changes aren't lost
+	actor, _ := currentUser(r)
+	updated, err := h.service.PatchTask(id, func(task model.Task) (model.ReplaceTask, error) {
+		target, err := json.Marshal(model.NewReplaceTask(task))
+		if err != nil {
+			return model.ReplaceTask{}, fmt.Errorf("failed to encode task %d: %w", id, err)
+		}
+
+		merged, err := model.MergePatch(target, patch)
+		if err != nil {
+			h.log.Info("failed to apply merge patch", slog.Any("err", err))
+			return model.ReplaceTask{}, &service.ValidationError{Fields: []service.FieldError{decodeFieldError(err)}}
+		}
+
+		var replacement model.ReplaceTask
+		if err := decodeStrict(bytes.NewReader(merged), &replacement); err != nil {
+			h.log.Info("failed to decode merged task", slog.Any("err", err))
+			return model.ReplaceTask{}, &service.ValidationError{Fields: []service.FieldError{decodeFieldError(err)}}
+		}
+		return replacement, nil
+	}, actor.Id)
+	if err != nil {
+		writeError(w, r, &h.log, err)
 		return
 	}
 
-	w.WriteHeader(http.StatusAccepted)
+	writeJSON(w, &h.log, http.StatusOK, &updated)
 }
 
 func (h TaskHandler) HandleDeleteTask(w http.ResponseWriter, r *http.Request) {
 	defer r.Body.Close()
 
-	id, err := strconv.Atoi(r.PathValue("id"))
-	if err != nil {
-		h.log.Error(fmt.Sprintf("invalid path variable id with value %s", r.PathValue("id")))
-		w.WriteHeader(http.StatusBadRequest)
+	id, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
 	}
 
-	if err := h.service.DeleteTask(id); err != nil {
-		h.log.Error("failed to process request: ", err)
-		w.WriteHeader(http.StatusInternalServerError)
+	actor, _ := currentUser(r)
+	if err := h.service.DeleteTask(id, actor.Id); err != nil {
+		writeError(w, r, &h.log, err)
+		return
 	}
 
 	w.WriteHeader(http.StatusNoContent)
 }
+
+// writeJSON writes v as the JSON body of the response with the given status code, with
+// its timestamps in the time zone of the request.
+func writeJSON(w http.ResponseWriter, log *slog.Logger, status int, v any) {
+	if localized, ok := w.(localizedWriter); ok && v != nil {
+		v = localize(reflect.ValueOf(v), localized.location).Interface()
+	}
+
+	jsonRes, err := json.Marshal(v)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to marshal json: %s", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if _, err := w.Write(jsonRes); err != nil {
+		log.Error(fmt.Sprintf("error when writing http response: %s", err))
+	}
+}
+
+// decodeJSON decodes the body of r into v, rejecting fields v doesn't have. It replies
+// with the field errors of the body when it can't be decoded.
+func decodeJSON(w http.ResponseWriter, r *http.Request, log *slog.Logger, v any) bool {
+	return decodeJSONFrom(w, r, log, r.Body, v)
+}
+
+// decodeJSONFrom is decodeJSON reading the JSON from body rather than from r.
+func decodeJSONFrom(w http.ResponseWriter, r *http.Request, log *slog.Logger, body io.Reader, v any) bool {
+	if err := decodeStrict(body, v); err != nil {
+		log.Info("failed to decode request body", slog.Any("err", err))
+		writeError(w, r, log, &service.ValidationError{Fields: []service.FieldError{decodeFieldError(err)}})
+		return false
+	}
+	return true
+}
+
+// decodeStrict decodes the single JSON value of body into v, failing on unknown fields.
+func decodeStrict(body io.Reader, v any) error {
+	decoder := json.NewDecoder(body)
+	decoder.DisallowUnknownFields()
+
+	err := decoder.Decode(v)
+	if err == nil && decoder.More() {
+		err = errors.New("unexpected data after the JSON value")
+	}
+	return err
+}
+
+// decodeFieldError describes a JSON decoding error as the error of the field it is about.
+func decodeFieldError(err error) service.FieldError {
+	var typeErr *json.UnmarshalTypeError
+	var syntaxErr *json.SyntaxError
+	switch {
+	case errors.As(err, &typeErr):
+		return service.FieldError{Field: typeErr.Field, Message: fmt.Sprintf("must be a %s", typeErr.Type)}
+	case errors.As(err, &syntaxErr):
+		return service.FieldError{Message: fmt.Sprintf("invalid JSON at offset %d: %s", syntaxErr.Offset, syntaxErr)}
+	case errors.Is(err, io.EOF):
+		return service.FieldError{Message: "request body is empty"}
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return service.FieldError{Message: "request body is incomplete JSON"}
+	}
+
+	if field, ok := strings.CutPrefix(err.Error(), "json: unknown field "); ok {
+		return service.FieldError{Field: strings.Trim(field, `"`), Message: "is not a known field"}
+	}
+	return service.FieldError{Message: err.Error()}
+}
+
+// pathInt parses the path variable name as an int, replying with 400 when it isn't one.
+func pathInt(w http.ResponseWriter, r *http.Request, log *slog.Logger, name string) (int, bool) {
+	value, err := strconv.Atoi(r.PathValue(name))
+	if err != nil {
+		log.Error(fmt.Sprintf("invalid path variable %s with value %s", name, r.PathValue(name)))
+		writeProblem(w, r, log, http.StatusBadRequest, fmt.Sprintf("invalid %s %s", name, r.PathValue(name)))
+		return 0, false
+	}
+	return value, true
+}
+
+// queryInt parses the query param name as an int, 0 when absent. It replies with 400
+// when the value isn't an int.
+func queryInt(w http.ResponseWriter, r *http.Request, log *slog.Logger, name string) (int, bool) {
+	param := r.URL.Query().Get(name)
+	if param == "" {
+		return 0, true
+	}
+
+	value, err := strconv.Atoi(param)
+	if err != nil {
+		log.Info(fmt.Sprintf("input %s is invalid for query param %s", param, name))
+		writeProblem(w, r, log, http.StatusBadRequest, fmt.Sprintf("input %s is invalid for query param %s", param, name))
+		return 0, false
+	}
+	return value, true
+}
+
+// queryDate parses the query param name as a date in the format 2006-01-02, zero when
+// absent. It replies with 400 when the value isn't a date.
+func queryDate(w http.ResponseWriter, r *http.Request, log *slog.Logger, name string) (time.Time, bool) {
+	param := r.URL.Query().Get(name)
+	if param == "" {
+		return time.Time{}, true
+	}
+
+	value, err := time.ParseInLocation(time.DateOnly, param, requestLocation(r))
+	if err != nil {
+		log.Info(fmt.Sprintf("input %s is invalid for query param %s", param, name))
+		writeProblem(w, r, log, http.StatusBadRequest, fmt.Sprintf("input %s is invalid for query param %s", param, name))
+		return time.Time{}, false
+	}
+	return value, true
+}
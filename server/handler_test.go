@@ -0,0 +1,23 @@
+package server
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandleGetUserTasks_InvalidSort(t *testing.T) {
+	// the sort is rejected before the service is called
+	h := TaskHandler{log: *slog.New(slog.NewTextHandler(io.Discard, nil))}
+
+	r := httptest.NewRequest(http.MethodGet, "/users/1/tasks?sort=color", nil)
+	r.SetPathValue("id", "1")
+	w := httptest.NewRecorder()
+	h.HandleGetUserTasks(w, r)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("with sort=color got status %d, but expected %d", w.Code, http.StatusBadRequest)
+	}
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"go-task-tracker/model"
+	"net/http"
+)
+
+func (h TaskHandler) HandlePostLink(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	id, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	var link model.LinkTask
+	if !decodeJSON(w, r, &h.log, &link) {
+		return
+	}
+
+	actor, _ := currentUser(r)
+	task, err := h.service.LinkTasks(id, link, actor.Id)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusOK, &task)
+}
+
+func (h TaskHandler) HandleDeleteLink(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	id, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	var link model.LinkTask
+	if !decodeJSON(w, r, &h.log, &link) {
+		return
+	}
+
+	actor, _ := currentUser(r)
+	if err := h.service.UnlinkTasks(id, link, actor.Id); err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h TaskHandler) HandleResolveDuplicate(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	id, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	var resolve model.ResolveDuplicate
+	if !decodeJSON(w, r, &h.log, &resolve) {
+		return
+	}
+
+	actor, _ := currentUser(r)
+	task, err := h.service.ResolveAsDuplicate(id, resolve.TaskId, actor.Id)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusOK, &task)
+}
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+	"net/http"
+	"strconv"
+)
+
+// taskPage reads the page of a task listing from the limit and cursor query params.
+func (h TaskHandler) taskPage(w http.ResponseWriter, r *http.Request) (model.TaskPage, bool) {
+	limit, ok := queryInt(w, r, &h.log, "limit")
+	if !ok {
+		return model.TaskPage{}, false
+	}
+	return model.TaskPage{Limit: limit, Cursor: r.URL.Query().Get("cursor")}, true
+}
+
+// writeTaskPage replies with the tasks of page shaped by view. The Link header points to
+// the first page and, unless this is the last one, to the next page. With count=true the
+// X-Total-Count header tells how many tasks all the pages hold.
+func (h TaskHandler) writeTaskPage(w http.ResponseWriter, r *http.Request, page model.TaskListPage, view taskView) {
+	count := r.URL.Query().Get("count")
+	if count != "" {
+		withCount, err := strconv.ParseBool(count)
+		if err != nil {
+			h.log.Info(fmt.Sprintf("input %s is invalid for query param count", count))
+			writeProblem(w, r, &h.log, http.StatusBadRequest, fmt.Sprintf("input %s is invalid for query param count", count))
+			return
+		}
+
+		if withCount {
+			w.Header().Set("X-Total-Count", strconv.Itoa(page.Total))
+		}
+	}
+
+	w.Header().Add("Link", fmt.Sprintf(`<%s>; rel="first"`, pageURL(r, "")))
+	if page.Next != "" {
+		w.Header().Add("Link", fmt.Sprintf(`<%s>; rel="next"`, pageURL(r, page.Next)))
+	}
+
+	if view.fields == nil && view.include == nil {
+		writeJSON(w, &h.log, http.StatusOK, &page.Items)
+		return
+	}
+
+	tasks := make([]model.Task, 0, len(page.Items))
+	values := make([]any, 0, len(page.Items))
+	for i := range page.Items {
+		tasks = append(tasks, page.Items[i].Task)
+		values = append(values, &page.Items[i])
+	}
+
+	shaped, ok := h.shapeTasks(w, r, view, tasks, values)
+	if !ok {
+		return
+	}
+	writeJSON(w, &h.log, http.StatusOK, &shaped)
+}
+
+// pageURL returns the URL of r starting at cursor, keeping the other query params.
+func pageURL(r *http.Request, cursor string) string {
+	query := r.URL.Query()
+	query.Del("cursor")
+	if cursor != "" {
+		query.Set("cursor", cursor)
+	}
+
+	if len(query) == 0 {
+		return r.URL.Path
+	}
+	return r.URL.Path + "?" + query.Encode()
+}
@@ -0,0 +1,118 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-task-tracker/model"
+	"go-task-tracker/service"
+	"log/slog"
+	"net/http"
+)
+
+// Problem is an RFC 7807 problem detail. Code is a stable identifier of the kind of
+// problem that clients can match on, unlike Detail, which is meant for people.
+type Problem struct {
+	Type     string               `json:"type"`
+	Title    string               `json:"title"`
+	Status   int                  `json:"status"`
+	Detail   string               `json:"detail,omitempty"`
+	Code     string               `json:"code"`
+	Instance string               `json:"instance,omitempty"`
+	Errors   []service.FieldError `json:"errors,omitempty"`
+}
+
+// problemCodes are the codes of the problems replied with each status.
+var problemCodes = map[int]string{
+	http.StatusBadRequest:            "bad_request",
+	http.StatusUnauthorized:          "unauthorized",
+	http.StatusForbidden:             "forbidden",
+	http.StatusNotFound:              "not_found",
+	http.StatusConflict:              "conflict",
+	http.StatusRequestEntityTooLarge: "attachment_too_large",
+	http.StatusUnsupportedMediaType:  "unsupported_media_type",
+	http.StatusInternalServerError:   "internal",
+	http.StatusServiceUnavailable:    "unavailable",
+}
+
+func newProblem(r *http.Request, status int, detail string) Problem {
+	code, ok := problemCodes[status]
+	if !ok {
+		code = "internal"
+	}
+
+	return Problem{
+		Type:     "about:blank",
+		Title:    http.StatusText(status),
+		Status:   status,
+		Detail:   detail,
+		Code:     code,
+		Instance: r.URL.Path,
+	}
+}
+
+// writeProblem replies with a problem of status, detail tells the client what went wrong.
+func writeProblem(w http.ResponseWriter, r *http.Request, log *slog.Logger, status int, detail string) {
+	sendProblem(w, log, newProblem(r, status, detail))
+}
+
+// writeError replies to a failed service call with the problem matching the kind of err.
+// The detail is the message of the service.Error in err, internal errors are never
+// described to the client.
+func writeError(w http.ResponseWriter, r *http.Request, log *slog.Logger, err error) {
+	var problem Problem
+	var validationErr *service.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		problem = newProblem(r, http.StatusBadRequest, "the input is invalid")
+		problem.Code = "validation_failed"
+		problem.Errors = validationErr.Fields
+	case errors.Is(err, service.ErrAttachmentTooLarge):
+		problem = newProblem(r, http.StatusRequestEntityTooLarge, userMessage(err))
+	case errors.Is(err, model.ErrValidation):
+		problem = newProblem(r, http.StatusBadRequest, userMessage(err))
+		problem.Code = "validation_failed"
+	case errors.Is(err, model.ErrForbidden):
+		problem = newProblem(r, http.StatusForbidden, userMessage(err))
+	case errors.Is(err, model.ErrNotFound):
+		problem = newProblem(r, http.StatusNotFound, userMessage(err))
+	case errors.Is(err, model.ErrConflict):
+		problem = newProblem(r, http.StatusConflict, userMessage(err))
+	case errors.Is(err, model.ErrUnavailable):
+		problem = newProblem(r, http.StatusServiceUnavailable, "storage is unavailable, try again later")
+	default:
+		problem = newProblem(r, http.StatusInternalServerError, "an unexpected error occurred")
+	}
+
+	if problem.Status >= http.StatusInternalServerError {
+		log.Error("failed to process request", slog.Any("err", err))
+	} else {
+		log.Info("rejected request", slog.Any("err", err))
+	}
+	sendProblem(w, log, problem)
+}
+
+// userMessage is the message for the client of the service.Error in err, empty when
+// there is none.
+func userMessage(err error) string {
+	var serviceErr service.Error
+	if errors.As(err, &serviceErr) {
+		return serviceErr.UserMsg
+	}
+	return ""
+}
+
+func sendProblem(w http.ResponseWriter, log *slog.Logger, problem Problem) {
+	body, err := json.Marshal(&problem)
+	if err != nil {
+		log.Error(fmt.Sprintf("failed to marshal problem: %s", err))
+		w.WriteHeader(http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/problem+json")
+	w.WriteHeader(problem.Status)
+	if _, err := w.Write(body); err != nil {
+		log.Error(fmt.Sprintf("error when writing http response: %s", err))
+	}
+}
@@ -0,0 +1,97 @@
+package server
+
+import (
+	"go-task-tracker/model"
+	"go-task-tracker/service"
+	"log/slog"
+	"net/http"
+	"net/url"
+)
+
+type ProjectHandler struct {
+	service service.ProjectService
+	log     slog.Logger
+}
+
+func NewProjectHandler(service service.ProjectService, log *slog.Logger) ProjectHandler {
+	h := ProjectHandler{
+		service: service,
+		log:     *log,
+	}
+	http.HandleFunc("POST /projects", h.HandlePostProject)
+	http.HandleFunc("GET /projects", h.HandleGetProjects)
+	http.HandleFunc("GET /projects/{key}", h.HandleGetProject)
+	http.HandleFunc("PUT /projects/{key}", h.HandleUpdateProject)
+	http.HandleFunc("DELETE /projects/{key}", h.HandleDeleteProject)
+	return h
+}
+
+func (h ProjectHandler) HandlePostProject(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var project model.CreateProject
+	if !decodeJSON(w, r, &h.log, &project) {
+		return
+	}
+
+	created, err := h.service.AddProject(project)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	w.Header().Set("Location", "/projects/"+url.PathEscape(created.Key))
+	writeJSON(w, &h.log, http.StatusCreated, &created)
+}
+
+func (h ProjectHandler) HandleGetProjects(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	projects, err := h.service.GetProjects()
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusOK, &projects)
+}
+
+func (h ProjectHandler) HandleGetProject(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	project, err := h.service.GetProject(r.PathValue("key"))
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusOK, &project)
+}
+
+func (h ProjectHandler) HandleUpdateProject(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var update model.UpdateProject
+	if !decodeJSON(w, r, &h.log, &update) {
+		return
+	}
+
+	project, err := h.service.UpdateProject(r.PathValue("key"), update)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusOK, &project)
+}
+
+func (h ProjectHandler) HandleDeleteProject(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	if err := h.service.DeleteProject(r.PathValue("key")); err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,116 @@
+package server
+
+import (
+	"encoding/csv"
+	"fmt"
+	"go-task-tracker/model"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// HandleGetBurndown returns the tasks and story points left to do at the end of each day,
+// for the tasks matching the filters of GET /tasks, see writeReport.
+func (h TaskHandler) HandleGetBurndown(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	filter, from, to, ok := h.reportParams(w, r)
+	if !ok {
+		return
+	}
+
+	points, err := h.service.GetBurndown(filter, from, to)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	rows := make([][]string, 0, len(points))
+	for _, point := range points {
+		rows = append(rows, []string{point.Date, strconv.Itoa(point.Tasks), strconv.Itoa(point.StoryPoints)})
+	}
+	writeReport(w, r, &h.log, "burndown", &points, []string{"Date", "Tasks", "StoryPoints"}, rows)
+}
+
+// HandleGetCumulativeFlow returns the number of tasks in each status at the end of each
+// day, for the tasks matching the filters of GET /tasks, see writeReport.
+func (h TaskHandler) HandleGetCumulativeFlow(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	filter, from, to, ok := h.reportParams(w, r)
+	if !ok {
+		return
+	}
+
+	points, err := h.service.GetCumulativeFlow(filter, from, to)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	rows := make([][]string, 0, len(points))
+	for _, point := range points {
+		rows = append(rows, []string{point.Date, strconv.Itoa(point.Todo), strconv.Itoa(point.InProgress), strconv.Itoa(point.Done)})
+	}
+	writeReport(w, r, &h.log, "cumulative-flow", &points, []string{"Date", "Todo", "InProgress", "Done"}, rows)
+}
+
+// reportParams reads the task filters of a report along with the from and to query
+// params, both inclusive.
+func (h TaskHandler) reportParams(w http.ResponseWriter, r *http.Request) (model.TaskFilter, time.Time, time.Time, bool) {
+	filter, ok := h.taskFilter(w, r)
+	if !ok {
+		return model.TaskFilter{}, time.Time{}, time.Time{}, false
+	}
+
+	from, ok := queryDate(w, r, &h.log, "from")
+	if !ok {
+		return model.TaskFilter{}, time.Time{}, time.Time{}, false
+	}
+
+	to, ok := queryDate(w, r, &h.log, "to")
+	if !ok {
+		return model.TaskFilter{}, time.Time{}, time.Time{}, false
+	}
+
+	if !to.IsZero() {
+		to = to.AddDate(0, 0, 1)
+	}
+	return filter, from, to, true
+}
+
+// writeReport replies with the report v as JSON, or as CSV made of header and rows when
+// the request asks for it with format=csv or an Accept header of text/csv. name is the
+// name of the CSV file.
+func writeReport(w http.ResponseWriter, r *http.Request, log *slog.Logger, name string, v any, header []string, rows [][]string) {
+	format := r.URL.Query().Get("format")
+	if format == "" && strings.Contains(r.Header.Get("Accept"), "text/csv") {
+		format = "csv"
+	}
+
+	switch format {
+	case "", "json":
+		writeJSON(w, log, http.StatusOK, v)
+		return
+	case "csv":
+	default:
+		log.Info(fmt.Sprintf("input %s is invalid for query param format", format))
+		writeProblem(w, r, log, http.StatusBadRequest, fmt.Sprintf("input %s is invalid for query param format, use json or csv", format))
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/csv; charset=utf-8")
+	w.Header().Set("Content-Disposition", fmt.Sprintf(`attachment; filename="%s.csv"`, name))
+	w.WriteHeader(http.StatusOK)
+
+	writer := csv.NewWriter(w)
+	if err := writer.Write(header); err != nil {
+		log.Error(fmt.Sprintf("error when writing http response: %s", err))
+		return
+	}
+	if err := writer.WriteAll(rows); err != nil {
+		log.Error(fmt.Sprintf("error when writing http response: %s", err))
+	}
+}
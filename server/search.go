@@ -0,0 +1,24 @@
+package server
+
+import (
+	"net/http"
+)
+
+// HandleSearch searches the descriptions and comments of the tasks for the words of the
+// query param q, returning at most limit results ranked by relevance.
+func (h TaskHandler) HandleSearch(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	limit, ok := queryInt(w, r, &h.log, "limit")
+	if !ok {
+		return
+	}
+
+	results, err := h.service.Search(r.URL.Query().Get("q"), limit)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusOK, &results)
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+	"go-task-tracker/service"
+	"log/slog"
+	"net/http"
+)
+
+type SprintHandler struct {
+	service service.SprintService
+	log     slog.Logger
+}
+
+func NewSprintHandler(service service.SprintService, log *slog.Logger) SprintHandler {
+	h := SprintHandler{
+		service: service,
+		log:     *log,
+	}
+	http.HandleFunc("POST /sprints", h.HandlePostSprint)
+	http.HandleFunc("GET /sprints", h.HandleGetSprints)
+	http.HandleFunc("GET /sprints/{id}", h.HandleGetSprint)
+	http.HandleFunc("PUT /sprints/{id}", h.HandleUpdateSprint)
+	http.HandleFunc("GET /sprints/{id}/scope", h.HandleGetSprintScope)
+	http.HandleFunc("GET /sprints/{id}/changes", h.HandleGetScopeChanges)
+	return h
+}
+
+func (h SprintHandler) HandlePostSprint(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var sprint model.CreateSprint
+	if !decodeJSON(w, r, &h.log, &sprint) {
+		return
+	}
+
+	created, err := h.service.AddSprint(sprint)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/sprints/%d", created.Id))
+	writeJSON(w, &h.log, http.StatusCreated, &created)
+}
+
+func (h SprintHandler) HandleGetSprints(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	sprints, err := h.service.GetSprints()
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusOK, &sprints)
+}
+
+func (h SprintHandler) HandleGetSprint(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	id, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	sprint, err := h.service.GetSprint(id)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusOK, &sprint)
+}
+
+func (h SprintHandler) HandleUpdateSprint(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	id, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	var update model.UpdateSprint
+	if !decodeJSON(w, r, &h.log, &update) {
+		return
+	}
+
+	sprint, err := h.service.UpdateSprint(id, update)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusOK, &sprint)
+}
+
+func (h SprintHandler) HandleGetSprintScope(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	id, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	scope, err := h.service.GetSprintScope(id)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusOK, &scope)
+}
+
+func (h SprintHandler) HandleGetScopeChanges(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	id, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	changes, err := h.service.GetScopeChanges(id)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusOK, &changes)
+}
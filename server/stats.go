@@ -0,0 +1,40 @@
+package server
+
+import (
+	"net/http"
+)
+
+// HandleGetStats summarizes the tasks matching the filters of GET /tasks: their number,
+// optionally grouped with group_by=status|tag|assignee|project, and for the period of the
+// from and to query params, both inclusive, the tasks created and completed per
+// interval=day|week along with lead and cycle times.
+func (h TaskHandler) HandleGetStats(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	filter, ok := h.taskFilter(w, r)
+	if !ok {
+		return
+	}
+
+	from, ok := queryDate(w, r, &h.log, "from")
+	if !ok {
+		return
+	}
+
+	to, ok := queryDate(w, r, &h.log, "to")
+	if !ok {
+		return
+	}
+
+	if !to.IsZero() {
+		to = to.AddDate(0, 0, 1)
+	}
+
+	stats, err := h.service.GetStats(filter, r.URL.Query().Get("group_by"), r.URL.Query().Get("interval"), from, to)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusOK, &stats)
+}
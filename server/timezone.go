@@ -0,0 +1,123 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"go-task-tracker/model"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"time"
+)
+
+type locationContextKey struct{}
+
+// localizedWriter carries the time zone of a request to writeJSON, which renders the
+// timestamps of responses in it.
+type localizedWriter struct {
+	http.ResponseWriter
+	location *time.Location
+}
+
+// Unwrap lets http.ResponseController reach the features of the wrapped writer.
+func (w localizedWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// Localize reads the IANA time zone of each request, such as Europe/Lisbon, from the
+// Time-Zone header or the tz query param. Timestamps in responses are written in that
+// zone and dates in query params are read in it. Requests without a zone use UTC.
+func Localize(log *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.URL.Query().Get("tz")
+		if name == "" {
+			name = r.Header.Get("Time-Zone")
+		}
+
+		if name == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		location, err := time.LoadLocation(name)
+		if err != nil {
+			log.Info("unknown time zone", slog.String("zone", name), slog.Any("err", err))
+			writeProblem(w, r, log, http.StatusBadRequest, fmt.Sprintf("unknown time zone %s", name))
+			return
+		}
+
+		ctx := context.WithValue(r.Context(), locationContextKey{}, location)
+		next.ServeHTTP(localizedWriter{ResponseWriter: w, location: location}, r.WithContext(ctx))
+	})
+}
+
+// requestLocation returns the time zone of r, UTC unless the request set one.
+func requestLocation(r *http.Request) *time.Location {
+	if location, ok := r.Context().Value(locationContextKey{}).(*time.Location); ok {
+		return location
+	}
+	return time.UTC
+}
+
+var dateTimeType = reflect.TypeOf(model.DateTime{})
+
+// localize returns a copy of v with every model.DateTime it reaches moved to location. v
+// is left as it is, as what it points to may be shared with other requests, so pointers,
+// slices and maps on the way to a DateTime are copied too.
+func localize(v reflect.Value, location *time.Location) reflect.Value {
+	switch v.Kind() {
+	case reflect.Pointer:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type().Elem())
+		copied.Elem().Set(localize(v.Elem(), location))
+		return copied
+	case reflect.Interface:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(localize(v.Elem(), location))
+		return copied
+	case reflect.Slice:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeSlice(v.Type(), v.Len(), v.Len())
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(localize(v.Index(i), location))
+		}
+		return copied
+	case reflect.Array:
+		copied := reflect.New(v.Type()).Elem()
+		for i := 0; i < v.Len(); i++ {
+			copied.Index(i).Set(localize(v.Index(i), location))
+		}
+		return copied
+	case reflect.Map:
+		if v.IsNil() {
+			return v
+		}
+		copied := reflect.MakeMapWithSize(v.Type(), v.Len())
+		for entries := v.MapRange(); entries.Next(); {
+			copied.SetMapIndex(entries.Key(), localize(entries.Value(), location))
+		}
+		return copied
+	case reflect.Struct:
+		if v.Type() == dateTimeType {
+			return reflect.ValueOf(model.DateTime(time.Time(v.Interface().(model.DateTime)).In(location)))
+		}
+
+		// unexported fields are copied as they are
+		copied := reflect.New(v.Type()).Elem()
+		copied.Set(v)
+		for i := 0; i < v.NumField(); i++ {
+			if v.Type().Field(i).IsExported() {
+				copied.Field(i).Set(localize(v.Field(i), location))
+			}
+		}
+		return copied
+	}
+	return v
+}
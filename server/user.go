@@ -0,0 +1,52 @@
+package server
+
+import (
+	"go-task-tracker/model"
+	"go-task-tracker/service"
+	"log/slog"
+	"net/http"
+)
+
+type UserHandler struct {
+	service service.UserService
+	log     slog.Logger
+}
+
+func NewUserHandler(service service.UserService, log *slog.Logger) UserHandler {
+	h := UserHandler{
+		service: service,
+		log:     *log,
+	}
+	http.HandleFunc("POST /users", h.HandlePostUser)
+	http.HandleFunc("GET /users", h.HandleGetUsers)
+	return h
+}
+
+func (h UserHandler) HandlePostUser(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	var user model.CreateUser
+	if !decodeJSON(w, r, &h.log, &user) {
+		return
+	}
+
+	created, err := h.service.AddUser(user)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusCreated, &created)
+}
+
+func (h UserHandler) HandleGetUsers(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	users, err := h.service.GetUsers()
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusOK, &users)
+}
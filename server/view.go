@@ -0,0 +1,136 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"go-task-tracker/model"
+	"maps"
+	"net/http"
+	"reflect"
+	"strings"
+)
+
+// taskFieldNames maps the lowercase JSON names of the fields of listed tasks to their names.
+var taskFieldNames = jsonFieldNames(reflect.TypeOf(model.TaskListItem{}))
+
+func jsonFieldNames(t reflect.Type) map[string]string {
+	names := make(map[string]string)
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			maps.Copy(names, jsonFieldNames(field.Type))
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "" {
+			name = field.Name
+		}
+		if field.IsExported() && name != "-" {
+			names[strings.ToLower(name)] = name
+		}
+	}
+	return names
+}
+
+// taskView shapes the tasks of a response. Fields lists the task fields written besides
+// the Id, every field when nil, and include the related resources embedded in each task.
+type taskView struct {
+	fields  []string
+	include []model.TaskInclude
+}
+
+// taskView reads the view of the tasks of a response from the query params fields, such as
+// fields=Id,Description,Status, and include, such as include=comments,assignee.
+func (h TaskHandler) taskView(w http.ResponseWriter, r *http.Request) (taskView, bool) {
+	var view taskView
+	if value := r.URL.Query().Get("fields"); value != "" {
+		view.fields = []string{"Id"}
+		for _, field := range strings.Split(value, ",") {
+			name, ok := taskFieldNames[strings.ToLower(field)]
+			if !ok {
+				h.log.Info(fmt.Sprintf("input %s is invalid for query param fields", value))
+				writeProblem(w, r, &h.log, http.StatusBadRequest, fmt.Sprintf("input %s is invalid for query param fields: tasks have no field %q", value, field))
+				return taskView{}, false
+			}
+			view.fields = append(view.fields, name)
+		}
+	}
+
+	if value := r.URL.Query().Get("include"); value != "" {
+		include, err := model.ParseTaskIncludes(value)
+		if err != nil {
+			h.log.Info(fmt.Sprintf("input %s is invalid for query param include: %s", value, err))
+			writeProblem(w, r, &h.log, http.StatusBadRequest, fmt.Sprintf("input %s is invalid for query param include: %s", value, err))
+			return taskView{}, false
+		}
+		view.include = include
+	}
+	return view, true
+}
+
+// shapedTask is Task, a *model.Task or a *model.TaskListItem, written with the fields
+// and relations of view.
+type shapedTask struct {
+	Task      any
+	Relations model.TaskRelations
+	view      taskView
+}
+
+func (t shapedTask) MarshalJSON() ([]byte, error) {
+	b, err := json.Marshal(t.Task)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+
+	if t.view.fields != nil {
+		maps.DeleteFunc(fields, func(name string, _ json.RawMessage) bool {
+			for _, field := range t.view.fields {
+				if field == name {
+					return false
+				}
+			}
+			return true
+		})
+	}
+
+	for _, include := range t.view.include {
+		var name string
+		var value any
+		switch include {
+		case model.IncludeComments:
+			name, value = "Comments", t.Relations.Comments
+		case model.IncludeAssignee:
+			name, value = "Assignee", t.Relations.Assignee
+		}
+
+		if fields[name], err = json.Marshal(value); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(fields)
+}
+
+// shapeTasks returns the tasks shaped by view, values holding what is written for each of
+// tasks. It replies with an error when the relations can't be read.
+func (h TaskHandler) shapeTasks(w http.ResponseWriter, r *http.Request, view taskView, tasks []model.Task, values []any) ([]shapedTask, bool) {
+	relations := make(map[int]model.TaskRelations)
+	if len(view.include) > 0 {
+		var err error
+		if relations, err = h.service.GetTaskRelations(tasks, view.include); err != nil {
+			writeError(w, r, &h.log, err)
+			return nil, false
+		}
+	}
+
+	shaped := make([]shapedTask, 0, len(tasks))
+	for i, task := range tasks {
+		shaped = append(shaped, shapedTask{Task: values[i], Relations: relations[task.Id], view: view})
+	}
+	return shaped, true
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"go-task-tracker/model"
+	"net/http"
+)
+
+// HandleStartTimer starts a timer for the authenticated user, the query param
+// progress=true moves a task in TODO to InProgress.
+func (h TaskHandler) HandleStartTimer(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	taskId, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	user, ok := currentUser(r)
+	if !ok {
+		h.log.Info("starting a timer requires an authenticated user")
+		writeProblem(w, r, &h.log, http.StatusUnauthorized, "starting a timer requires an authenticated user")
+		return
+	}
+
+	autoProgress := r.URL.Query().Get("progress") == "true"
+	workLog, err := h.service.StartTimer(taskId, user.Id, autoProgress)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusCreated, &workLog)
+}
+
+func (h TaskHandler) HandleStopTimer(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	taskId, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	user, ok := currentUser(r)
+	if !ok {
+		h.log.Info("stopping a timer requires an authenticated user")
+		writeProblem(w, r, &h.log, http.StatusUnauthorized, "stopping a timer requires an authenticated user")
+		return
+	}
+
+	workLog, err := h.service.StopTimer(taskId, user.Id)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusOK, &workLog)
+}
+
+func (h TaskHandler) HandlePostWorkLog(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	taskId, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	user, ok := currentUser(r)
+	if !ok {
+		h.log.Info("logging work requires an authenticated user")
+		writeProblem(w, r, &h.log, http.StatusUnauthorized, "logging work requires an authenticated user")
+		return
+	}
+
+	var workLog model.CreateWorkLog
+	if !decodeJSON(w, r, &h.log, &workLog) {
+		return
+	}
+
+	created, err := h.service.AddWorkLog(taskId, user.Id, workLog)
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusCreated, &created)
+}
+
+func (h TaskHandler) HandleGetWorkLogs(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	taskId, ok := pathInt(w, r, &h.log, "id")
+	if !ok {
+		return
+	}
+
+	workLogs, err := h.service.GetWorkLogs(model.WorkLogFilter{TaskId: taskId})
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusOK, &workLogs)
+}
+
+// HandleGetWorkLogTotals sums logged work, optionally grouped with group_by=task|user|date
+// and narrowed with the task, user, from and to query params. Both dates are inclusive.
+func (h TaskHandler) HandleGetWorkLogTotals(w http.ResponseWriter, r *http.Request) {
+	defer r.Body.Close()
+
+	filter := model.WorkLogFilter{Location: requestLocation(r)}
+	var ok bool
+	if filter.TaskId, ok = queryInt(w, r, &h.log, "task"); !ok {
+		return
+	}
+
+	if filter.UserId, ok = queryInt(w, r, &h.log, "user"); !ok {
+		return
+	}
+
+	if filter.From, ok = queryDate(w, r, &h.log, "from"); !ok {
+		return
+	}
+
+	if filter.To, ok = queryDate(w, r, &h.log, "to"); !ok {
+		return
+	}
+
+	if !filter.To.IsZero() {
+		filter.To = filter.To.AddDate(0, 0, 1)
+	}
+
+	totals, err := h.service.GetWorkLogTotals(filter, r.URL.Query().Get("group_by"))
+	if err != nil {
+		writeError(w, r, &h.log, err)
+		return
+	}
+
+	writeJSON(w, &h.log, http.StatusOK, &totals)
+}
@@ -0,0 +1,96 @@
+package service
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+	"log/slog"
+	"time"
+)
+
+// ArchiveTasks moves the tasks done for longer than Config.ArchiveAfter out of the task
+// list into the archive, returning how many were archived. Tasks are archived before they
+// are removed, so a failure in between leaves them in both places instead of losing them.
+func (s *TaskService) ArchiveTasks() (int, error) {
+	if s.config.ArchiveAfter == 0 {
+		return 0, nil
+	}
+
+	s.taskMutex.Lock()
+	defer s.taskMutex.Unlock()
+
+	tasks, err := s.repository.GetAllTasks()
+	if err != nil {
+		s.log.Error("failed to get tasks to archive", slog.Any("err", err))
+		return 0, fmt.Errorf("failed to archive tasks: %w", err)
+	}
+
+	cutoff := time.Now().Add(-s.config.ArchiveAfter)
+	archived := make([]model.Task, 0)
+	ids := make([]int, 0)
+	for _, task := range tasks {
+		if task.Status == model.Done && task.CompletionTime().Before(cutoff) {
+			archived = append(archived, task)
+			ids = append(ids, task.Id)
+		}
+	}
+
+	if len(archived) == 0 {
+		return 0, nil
+	}
+
+	if err := s.archive.ArchiveTasks(archived); err != nil {
+		s.log.Error("failed to archive tasks", slog.Any("err", err))
+		return 0, fmt.Errorf("failed to archive tasks: %w", err)
+	}
+
+	if err := s.repository.RemoveTasks(ids); err != nil {
+		s.log.Error("failed to remove archived tasks", slog.Any("err", err))
+		return 0, fmt.Errorf("failed to archive tasks: %w", err)
+	}
+
+	for _, task := range archived {
+		s.recordRevision(model.TaskArchived, task, task, 0)
+		s.searchIndex.Delete(task.Id)
+	}
+	s.log.Info(fmt.Sprintf("Archived %d tasks done before %s", len(archived), cutoff.Format(time.DateTime)))
+	return len(archived), nil
+}
+
+// GetArchivedTasks returns the page of the archived tasks matching filter that were
+// completed from from and before to, ordered by sort. A zero from or to leaves that end open.
+func (s *TaskService) GetArchivedTasks(filter model.TaskFilter, sort model.TaskSort, page model.TaskPage, from time.Time, to time.Time) (model.TaskListPage, error) {
+	if err := validatePage(page, sort); err != nil {
+		s.log.Info(fmt.Sprintf("invalid page: %s", err))
+		return model.TaskListPage{}, err
+	}
+
+	matcher, err := s.newTaskMatcher(filter)
+	if err != nil {
+		return model.TaskListPage{}, err
+	}
+
+	tasks, err := s.archive.GetArchivedTasks(from, to)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to get archived tasks using filters %+v", filter), slog.Any("err", err))
+		return model.TaskListPage{}, fmt.Errorf("failed to get archived tasks: %w", err)
+	}
+
+	items := make([]model.TaskListItem, 0, len(tasks))
+	for _, task := range tasks {
+		completed := task.CompletionTime()
+		if (!from.IsZero() && completed.Before(from)) || (!to.IsZero() && !completed.Before(to)) {
+			continue
+		}
+
+		if matcher.matches(task) {
+			items = append(items, model.TaskListItem{Task: task, ChecklistProgress: model.Progress(task.Checklist)})
+		}
+	}
+
+	result, err := paginate(items, sort, page)
+	if err != nil {
+		s.log.Error("failed to page archived tasks", slog.Any("err", err))
+		return model.TaskListPage{}, fmt.Errorf("failed to get archived tasks: %w", err)
+	}
+	return result, nil
+}
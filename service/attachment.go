@@ -0,0 +1,178 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-task-tracker/model"
+	"io"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// blobGracePeriod keeps recently written blobs from being collected before the
+// attachment referencing them is stored.
+const blobGracePeriod = 10 * time.Minute
+
+// ErrAttachmentTooLarge is returned when an attachment exceeds the size limits in Config.
+var ErrAttachmentTooLarge = errors.New("attachment too large")
+
+// AddAttachment stores content as a new attachment of the task taskId.
+func (s *TaskService) AddAttachment(taskId int, uploaderId int, filename string, contentType string, content io.Reader) (model.Attachment, error) {
+	if _, err := s.findTask(taskId); err != nil {
+		s.log.Error(fmt.Sprintf("failed to attach file to task %d: %s", taskId, err))
+		return model.Attachment{}, NewError(err, "task does not exist")
+	}
+
+	hash, size, err := s.blobs.Put(io.LimitReader(content, s.config.MaxAttachmentSize+1))
+	if err != nil {
+		err = fmt.Errorf("failed to store attachment content: %w", err)
+		s.log.Error(err.Error())
+		return model.Attachment{}, NewError(err, "error when storing attachment")
+	}
+
+	// blobs of rejected uploads are left to CollectGarbage, as an upload of the same content
+	// may be about to reference them
+	if size > s.config.MaxAttachmentSize {
+		err := fmt.Errorf("%w: file exceeds %d bytes", ErrAttachmentTooLarge, s.config.MaxAttachmentSize)
+		return model.Attachment{}, NewError(err, err.Error())
+	}
+
+	s.attachmentMutex.Lock()
+	defer s.attachmentMutex.Unlock()
+
+	attachments, err := s.attachments.GetAttachments(taskId)
+	if err != nil {
+		err = fmt.Errorf("failed to get attachments of task %d: %w", taskId, err)
+		s.log.Error(err.Error())
+		return model.Attachment{}, NewError(err, "error when storing attachment")
+	}
+
+	total := size
+	for _, attachment := range attachments {
+		total += attachment.Size
+	}
+
+	if total > s.config.MaxTaskAttachmentsSize {
+		err := fmt.Errorf("%w: attachments of task exceed %d bytes", ErrAttachmentTooLarge, s.config.MaxTaskAttachmentsSize)
+		return model.Attachment{}, NewError(err, err.Error())
+	}
+
+	attachment, err := s.attachments.AddAttachment(model.Attachment{
+		TaskId:      taskId,
+		Filename:    filename,
+		ContentType: contentType,
+		Size:        size,
+		Hash:        hash,
+		UploaderId:  uploaderId,
+		CreatedAt:   model.Now(),
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to create attachment: %w", err)
+		s.log.Error(err.Error())
+		return model.Attachment{}, NewError(err, "error when storing attachment")
+	}
+	return attachment, nil
+}
+
+func (s *TaskService) GetAttachments(taskId int) ([]model.Attachment, error) {
+	attachments, err := s.attachments.GetAttachments(taskId)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to get attachments of task %d", taskId), slog.Any("err", err))
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+	return attachments, nil
+}
+
+// OpenAttachment returns an attachment of the task taskId along with its content, which
+// the caller must close.
+func (s *TaskService) OpenAttachment(taskId int, attachmentId int) (model.Attachment, *os.File, error) {
+	attachment, err := s.taskAttachment(taskId, attachmentId)
+	if err != nil {
+		return model.Attachment{}, nil, err
+	}
+
+	content, err := s.blobs.Open(attachment.Hash)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to open content of attachment %d: %s", attachmentId, err))
+		return model.Attachment{}, nil, fmt.Errorf("failed to open attachment: %w", err)
+	}
+	return attachment, content, nil
+}
+
+func (s *TaskService) DeleteAttachment(taskId int, attachmentId int) error {
+	if _, err := s.taskAttachment(taskId, attachmentId); err != nil {
+		return err
+	}
+
+	if err := s.attachments.DeleteAttachment(attachmentId); err != nil {
+		s.log.Error(fmt.Sprintf("failed to delete attachment %d: %s", attachmentId, err))
+		return fmt.Errorf("failed to delete attachment: %w", err)
+	}
+
+	if err := s.CollectGarbage(); err != nil {
+		s.log.Error(fmt.Sprintf("failed to collect blob of attachment %d: %s", attachmentId, err))
+	}
+	return nil
+}
+
+// CollectGarbage deletes the blobs no attachment references anymore.
+func (s *TaskService) CollectGarbage() error {
+	s.attachmentMutex.Lock()
+	defer s.attachmentMutex.Unlock()
+
+	referenced, err := s.referencedBlobs()
+	if err != nil {
+		return err
+	}
+
+	blobs, err := s.blobs.List()
+	if err != nil {
+		return fmt.Errorf("failed to list blobs: %w", err)
+	}
+
+	collected := 0
+	for hash, modTime := range blobs {
+		if referenced[hash] || time.Since(modTime) < blobGracePeriod {
+			continue
+		}
+
+		if err := s.blobs.Delete(hash); err != nil {
+			return fmt.Errorf("failed to collect blob: %w", err)
+		}
+		collected++
+	}
+
+	if collected > 0 {
+		s.log.Info(fmt.Sprintf("Collected %d unreferenced blobs", collected))
+	}
+	return nil
+}
+
+func (s *TaskService) referencedBlobs() (map[string]bool, error) {
+	attachments, err := s.attachments.GetAllAttachments()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get attachments: %w", err)
+	}
+
+	referenced := make(map[string]bool, len(attachments))
+	for _, attachment := range attachments {
+		referenced[attachment.Hash] = true
+	}
+	return referenced, nil
+}
+
+func (s *TaskService) taskAttachment(taskId int, attachmentId int) (model.Attachment, error) {
+	attachment, err := s.attachments.GetAttachment(attachmentId)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to get attachment %d: %s", attachmentId, err))
+		return model.Attachment{}, fmt.Errorf("failed to get attachment: %w", err)
+	}
+
+	if attachment.TaskId != taskId {
+		err := fmt.Errorf("attachment %d does not belong to task %d", attachmentId, taskId)
+		s.log.Error(err.Error())
+		return model.Attachment{}, notFoundError(err, "attachment does not exist")
+	}
+	return attachment, nil
+}
@@ -0,0 +1,171 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-task-tracker/model"
+	"slices"
+)
+
+func (s *TaskService) AddChecklistItem(taskId int, newItem model.CreateChecklistItem, actorId int) (model.ChecklistItem, error) {
+	if err := validateChecklistItem(newItem); err != nil {
+		s.log.Info(fmt.Sprintf("invalid checklist item: %s", err))
+		return model.ChecklistItem{}, err
+	}
+
+	var item model.ChecklistItem
+	_, err := s.updateChecklist(taskId, actorId, false, func(checklist []model.ChecklistItem) ([]model.ChecklistItem, error) {
+		if len(checklist) >= maxChecklistItems {
+			err := fmt.Errorf("checklist must have at most %d items", maxChecklistItems)
+			return nil, invalidError(err, err.Error())
+		}
+
+		item = model.ChecklistItem{Id: nextChecklistItemId(checklist), Text: newItem.Text}
+		return append(checklist, item), nil
+	})
+	return item, err
+}
+
+// ReorderChecklist puts the checklist items of the task taskId in the order of ids, which
+// must list every item exactly once.
+func (s *TaskService) ReorderChecklist(taskId int, ids []int, actorId int) ([]model.ChecklistItem, error) {
+	task, err := s.updateChecklist(taskId, actorId, false, func(checklist []model.ChecklistItem) ([]model.ChecklistItem, error) {
+		if len(ids) != len(checklist) {
+			return nil, invalidError(errors.New("reorder must list every checklist item"), "reorder must list every checklist item")
+		}
+
+		reordered := make([]model.ChecklistItem, 0, len(checklist))
+		for _, id := range ids {
+			index := slices.IndexFunc(checklist, func(item model.ChecklistItem) bool { return item.Id == id })
+			if index == -1 || slices.ContainsFunc(reordered, func(item model.ChecklistItem) bool { return item.Id == id }) {
+				err := fmt.Errorf("checklist item %d is unknown or repeated", id)
+				return nil, invalidError(err, err.Error())
+			}
+			reordered = append(reordered, checklist[index])
+		}
+		return reordered, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return task.Checklist, nil
+}
+
+// CheckChecklistItem checks or unchecks an item. Checking the last unchecked item of a task
+// set to CompleteWithChecklist moves it to Done, when the workflow of its project doesn't
+// allow it the item is left unchecked.
+func (s *TaskService) CheckChecklistItem(taskId int, itemId int, checked bool, actorId int) (model.ChecklistItem, error) {
+	var item model.ChecklistItem
+	_, err := s.updateChecklist(taskId, actorId, checked, func(checklist []model.ChecklistItem) ([]model.ChecklistItem, error) {
+		index, err := checklistItemIndex(checklist, itemId)
+		if err != nil {
+			return nil, err
+		}
+
+		checklist[index].Checked = checked
+		checklist[index].CheckedAt = nil
+		if checked {
+			now := model.Now()
+			checklist[index].CheckedAt = &now
+		}
+		item = checklist[index]
+		return checklist, nil
+	})
+	if err != nil {
+		return model.ChecklistItem{}, err
+	}
+	return item, nil
+}
+
+func (s *TaskService) DeleteChecklistItem(taskId int, itemId int, actorId int) error {
+	_, err := s.updateChecklist(taskId, actorId, false, func(checklist []model.ChecklistItem) ([]model.ChecklistItem, error) {
+		index, err := checklistItemIndex(checklist, itemId)
+		if err != nil {
+			return nil, err
+		}
+		return slices.Delete(checklist, index, index+1), nil
+	})
+	return err
+}
+
+// updateChecklist replaces the checklist of the task taskId with what change returns. With
+// complete set, a task set to CompleteWithChecklist whose items are then all checked is
+// moved to Done in the same update.
+func (s *TaskService) updateChecklist(taskId int, actorId int, complete bool, change func([]model.ChecklistItem) ([]model.ChecklistItem, error)) (model.Task, error) {
+	s.taskMutex.Lock()
+	defer s.taskMutex.Unlock()
+
+	task, err := s.findTask(taskId)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to update checklist of task %d: %s", taskId, err))
+		return model.Task{}, fmt.Errorf("failed to update checklist: %w", err)
+	}
+
+	checklist, err := change(slices.Clone(task.Checklist))
+	if err != nil {
+		s.log.Info(fmt.Sprintf("failed to update checklist of task %d: %s", taskId, err))
+		return model.Task{}, err
+	}
+
+	update := model.UpdateTask{Checklist: &checklist}
+	progress := model.Progress(checklist)
+	if complete && task.CompleteWithChecklist && task.Status != model.Done && progress.Checked == progress.Total {
+		// the workflow is checked before anything is saved, a rejected move keeps the checklist as it was
+		if err := s.checkWorkflow(task, model.Done); err != nil {
+			return model.Task{}, err
+		}
+		s.log.Info(fmt.Sprintf("Every checklist item of task %d is checked, completing it", taskId))
+		done := model.Done
+		update.Status = &done
+	}
+
+	updated, err := s.saveUpdate(task, update, model.TaskUpdated, actorId)
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	if updated.Recurrence != nil && task.Status != model.Done && updated.Status == model.Done {
+		return s.addNextOccurrence(updated)
+	}
+	return updated, nil
+}
+
+func checklistItemIndex(checklist []model.ChecklistItem, itemId int) (int, error) {
+	index := slices.IndexFunc(checklist, func(item model.ChecklistItem) bool { return item.Id == itemId })
+	if index == -1 {
+		err := fmt.Errorf("checklist item with id %d does not exists", itemId)
+		return -1, notFoundError(err, "checklist item does not exist")
+	}
+	return index, nil
+}
+
+func nextChecklistItemId(checklist []model.ChecklistItem) int {
+	id := 0
+	for _, item := range checklist {
+		id = max(id, item.Id)
+	}
+	return id + 1
+}
+
+// newChecklist creates a checklist item for every text.
+func newChecklist(texts []string) []model.ChecklistItem {
+	checklist := make([]model.ChecklistItem, 0, len(texts))
+	for i, text := range texts {
+		checklist = append(checklist, model.ChecklistItem{Id: i + 1, Text: text})
+	}
+
+	if len(checklist) == 0 {
+		return nil
+	}
+	return checklist
+}
+
+// uncheckedChecklist copies checklist with every item unchecked.
+func uncheckedChecklist(checklist []model.ChecklistItem) []model.ChecklistItem {
+	unchecked := slices.Clone(checklist)
+	for i := range unchecked {
+		unchecked[i].Checked = false
+		unchecked[i].CheckedAt = nil
+	}
+	return unchecked
+}
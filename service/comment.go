@@ -0,0 +1,105 @@
+package service
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+	"log/slog"
+)
+
+func (s *TaskService) AddComment(taskId int, authorId int, newComment model.CreateComment) (model.Comment, error) {
+	if err := validateComment(newComment.Body); err != nil {
+		s.log.Info(fmt.Sprintf("invalid comment: %s", err))
+		return model.Comment{}, err
+	}
+
+	if _, err := s.findTask(taskId); err != nil {
+		s.log.Error(fmt.Sprintf("failed to comment on task %d: %s", taskId, err))
+		return model.Comment{}, NewError(err, "task does not exist")
+	}
+
+	comment, err := s.comments.AddComment(model.Comment{
+		TaskId:    taskId,
+		AuthorId:  authorId,
+		Body:      newComment.Body,
+		CreatedAt: model.Now(),
+		UpdatedAt: model.Now(),
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to create comment: %w", err)
+		s.log.Error(err.Error())
+		return model.Comment{}, NewError(err, "error when creating comment")
+	}
+	s.reindexTask(taskId)
+	return comment, nil
+}
+
+func (s *TaskService) GetComments(taskId int) ([]model.Comment, error) {
+	if _, err := s.findTask(taskId); err != nil {
+		s.log.Info(fmt.Sprintf("failed to get comments of task %d: %s", taskId, err))
+		return nil, err
+	}
+
+	comments, err := s.comments.GetComments(taskId)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to get comments of task %d", taskId), slog.Any("err", err))
+		return nil, fmt.Errorf("failed to get comments: %w", err)
+	}
+	return comments, nil
+}
+
+// UpdateComment replaces the body of a comment, only its author is allowed to do so.
+func (s *TaskService) UpdateComment(taskId int, commentId int, authorId int, update model.UpdateComment) (model.Comment, error) {
+	if err := validateComment(update.Body); err != nil {
+		s.log.Info(fmt.Sprintf("invalid comment: %s", err))
+		return model.Comment{}, err
+	}
+
+	comment, err := s.authoredComment(taskId, commentId, authorId)
+	if err != nil {
+		return model.Comment{}, err
+	}
+
+	comment.Body = update.Body
+	comment.UpdatedAt = model.Now()
+	if err := s.comments.UpdateComment(comment); err != nil {
+		s.log.Error(fmt.Sprintf("error when updating comment: %s", err))
+		return model.Comment{}, fmt.Errorf("failed to update comment: %w", err)
+	}
+	s.reindexTask(taskId)
+	return comment, nil
+}
+
+// DeleteComment removes a comment, only its author is allowed to do so.
+func (s *TaskService) DeleteComment(taskId int, commentId int, authorId int) error {
+	if _, err := s.authoredComment(taskId, commentId, authorId); err != nil {
+		return err
+	}
+
+	if err := s.comments.DeleteComment(commentId); err != nil {
+		s.log.Error(fmt.Sprintf("failed to delete comment %d: %s", commentId, err))
+		return fmt.Errorf("failed to delete comment: %w", err)
+	}
+	s.reindexTask(taskId)
+	return nil
+}
+
+func (s *TaskService) authoredComment(taskId int, commentId int, authorId int) (model.Comment, error) {
+	comment, err := s.comments.GetComment(commentId)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to get comment %d: %s", commentId, err))
+		return model.Comment{}, fmt.Errorf("failed to get comment: %w", err)
+	}
+
+	if comment.TaskId != taskId {
+		err := fmt.Errorf("comment %d does not belong to task %d", commentId, taskId)
+		s.log.Error(err.Error())
+		return model.Comment{}, notFoundError(err, "comment does not exist")
+	}
+
+	if comment.AuthorId != authorId {
+		err := fmt.Errorf("user %d is not the author of comment %d", authorId, commentId)
+		s.log.Error(err.Error())
+		return model.Comment{}, forbiddenError(err, "only the author can change a comment")
+	}
+	return comment, nil
+}
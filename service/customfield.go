@@ -0,0 +1,251 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-task-tracker/model"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// checkFieldDefinitions fails when a custom field definition of a project is invalid or
+// its name is used twice.
+func checkFieldDefinitions(fields []model.FieldDefinition) error {
+	for i, field := range fields {
+		if err := field.Validate(); err != nil {
+			return invalidError(err, err.Error())
+		}
+
+		if slices.ContainsFunc(fields[:i], func(d model.FieldDefinition) bool { return d.Name == field.Name }) {
+			err := fmt.Errorf("field %s is defined twice", field.Name)
+			return invalidError(err, err.Error())
+		}
+	}
+	return nil
+}
+
+// checkCustomFields validates values against the custom fields of project and returns
+// them normalized. Nil values, which remove a field on update, are kept as they are but
+// can't remove a required field.
+func (s *TaskService) checkCustomFields(project model.Project, values map[string]any) (map[string]any, error) {
+	if len(values) == 0 {
+		return values, nil
+	}
+
+	if project.Id == 0 {
+		err := errors.New("tasks outside of a project have no custom fields")
+		s.log.Info(err.Error())
+		return nil, invalidError(err, err.Error())
+	}
+
+	normalized := make(map[string]any, len(values))
+	for name, value := range values {
+		field, ok := project.Field(name)
+		if !ok {
+			err := fmt.Errorf("project %s has no field %s", project.Key, name)
+			s.log.Info(err.Error())
+			return nil, invalidError(err, err.Error())
+		}
+
+		if value == nil {
+			if field.Required {
+				err := fmt.Errorf("field %s is required", name)
+				s.log.Info(err.Error())
+				return nil, invalidError(err, err.Error())
+			}
+			normalized[name] = nil
+			continue
+		}
+
+		value, err := field.Normalize(value)
+		if err != nil {
+			s.log.Info(err.Error())
+			return nil, invalidError(err, err.Error())
+		}
+
+		if field.Type == model.FieldUser {
+			if _, err := s.users.GetUser(int(value.(float64))); err != nil {
+				err = fmt.Errorf("failed to find user of field %s: %w", name, err)
+				s.log.Error(err.Error())
+				return nil, invalidError(err, fmt.Sprintf("user of field %s does not exist", name))
+			}
+		}
+		normalized[name] = value
+	}
+	return normalized, nil
+}
+
+// checkRequiredFields fails when values lacks a required custom field of project.
+func (s *TaskService) checkRequiredFields(project model.Project, values map[string]any) error {
+	for _, field := range project.Fields {
+		if _, ok := values[field.Name]; field.Required && !ok {
+			err := fmt.Errorf("field %s is required", field.Name)
+			s.log.Info(err.Error())
+			return invalidError(err, err.Error())
+		}
+	}
+	return nil
+}
+
+// strayCustomFields returns the custom fields of task that don't fit the fields of project,
+// mapped to nil so that an update removes them.
+func strayCustomFields(task model.Task, project model.Project) map[string]any {
+	stray := make(map[string]any)
+	for name, value := range task.CustomFields {
+		field, ok := project.Field(name)
+		if !ok {
+			stray[name] = nil
+			continue
+		}
+
+		if _, err := field.Normalize(value); err != nil {
+			stray[name] = nil
+		}
+	}
+
+	if len(stray) == 0 {
+		return nil
+	}
+	return stray
+}
+
+func matchesCustomFields(task model.Task, filter map[string]string) bool {
+	for name, want := range filter {
+		value, ok := task.CustomFields[name]
+		if !ok || model.FormatFieldValue(value) != want {
+			return false
+		}
+	}
+	return true
+}
+
+// sortKeys returns the keys tasks are ordered by for sort: its own followed by the id,
+// which tells every task apart so listings have a single order.
+func sortKeys(sort model.TaskSort) model.TaskSort {
+	if slices.ContainsFunc(sort, func(key model.TaskSortKey) bool { return key.Field == "id" }) {
+		return sort
+	}
+	return append(slices.Clip(sort), model.TaskSortKey{Field: "id"})
+}
+
+// sortTasks orders tasks by sort and then by id, keeping tasks without a value for a sort
+// field after those with one. It returns the values of each task for the sortKeys.
+func sortTasks(tasks []model.TaskListItem, sort model.TaskSort) map[int][]any {
+	keys := sortKeys(sort)
+	values := make(map[int][]any, len(tasks))
+	for _, task := range tasks {
+		values[task.Id] = sortValues(task.Task, keys)
+	}
+
+	slices.SortFunc(tasks, func(a model.TaskListItem, b model.TaskListItem) int {
+		return compareSortValues(values[a.Id], values[b.Id], keys)
+	})
+	return values
+}
+
+// compareSortValues orders the values a and b of two tasks for keys.
+func compareSortValues(a []any, b []any, keys model.TaskSort) int {
+	for i, key := range keys {
+		if a[i] == nil || b[i] == nil {
+			if (a[i] == nil) != (b[i] == nil) {
+				// the task with a value comes first
+				if a[i] != nil {
+					return -1
+				}
+				return 1
+			}
+			continue
+		}
+
+		order := model.CompareFieldValues(a[i], b[i])
+		if key.Descending {
+			order = -order
+		}
+		if order != 0 {
+			return order
+		}
+	}
+	return 0
+}
+
+func sortValues(task model.Task, keys model.TaskSort) []any {
+	values := make([]any, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, taskSortValue(task, key.Field))
+	}
+	return values
+}
+
+// sortTimeLayout writes times in UTC with a fixed width, so their text sorts chronologically.
+const sortTimeLayout = "2006-01-02T15:04:05.000000000"
+
+// taskSortValue returns the value of task sorted on for field, nil when the task has none.
+func taskSortValue(task model.Task, field string) any {
+	switch field {
+	case "id":
+		return task.Id
+	case "key":
+		return keySortValue(task.Key)
+	case "description":
+		return strings.ToLower(task.Description)
+	case "status":
+		return int(task.Status)
+	case "assigneeId":
+		return optional(task.AssigneeId)
+	case "reporterId":
+		return optional(task.ReporterId)
+	case "sprintId":
+		return optional(task.SprintId)
+	case "dueAt":
+		return sortTime(task.DueAt)
+	case "storyPoints":
+		return task.StoryPoints
+	case "originalEstimate":
+		return int(task.OriginalEstimate)
+	case "remainingEstimate":
+		return int(task.RemainingEstimate)
+	case "createdAt":
+		return sortTime(&task.CreatedAt)
+	case "updatedAt":
+		return sortTime(&task.UpdatedAt)
+	case "startedAt":
+		return sortTime(task.StartedAt)
+	case "completedAt":
+		return sortTime(task.CompletedAt)
+	}
+	return task.CustomFields[strings.TrimPrefix(field, "cf.")]
+}
+
+// keySortValue returns a task key with its number zero padded, so keys sort by project and
+// then by number, OPS-2 before OPS-10.
+func keySortValue(key string) any {
+	project, number, ok := strings.Cut(key, "-")
+	if !ok {
+		return optional(key)
+	}
+
+	n, err := strconv.Atoi(number)
+	if err != nil {
+		return key
+	}
+	// project keys are letters and digits, which sort after the dash
+	return fmt.Sprintf("%s-%019d", project, n)
+}
+
+// optional returns nil for the zero value, which stands for no value in fields such as ids.
+func optional[T comparable](value T) any {
+	var zero T
+	if value == zero {
+		return nil
+	}
+	return value
+}
+
+func sortTime(dateTime *model.DateTime) any {
+	if dateTime == nil {
+		return nil
+	}
+	return time.Time(*dateTime).UTC().Format(sortTimeLayout)
+}
@@ -0,0 +1,109 @@
+package service
+
+import (
+	"cmp"
+	"fmt"
+	"go-task-tracker/model"
+	"slices"
+	"time"
+)
+
+// Groups accepted by GetEstimateTotals, along with GroupByTask and GroupByUser.
+const (
+	GroupByStatus = "status"
+	GroupByTag    = "tag"
+)
+
+// GetEstimateTotals sums the estimates of the tasks matching filter, grouped by status or
+// tag. A task with several tags counts towards each of them and untagged tasks are left
+// out of the tag groups. An empty groupBy returns a single total.
+func (s *TaskService) GetEstimateTotals(filter model.TaskFilter, groupBy string) ([]model.EstimateTotal, error) {
+	if groupBy != "" && groupBy != GroupByStatus && groupBy != GroupByTag {
+		err := fmt.Errorf("invalid group %q", groupBy)
+		s.log.Info(err.Error())
+		return nil, invalidError(err, "estimates can be grouped by status or tag")
+	}
+
+	matcher, err := s.newTaskMatcher(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	tasks, err := s.findTasks(matcher)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get estimates: %w", err)
+	}
+
+	type groupKey struct {
+		status model.TaskStatus
+		tag    string
+	}
+	totals := make(map[groupKey]*model.EstimateTotal)
+	add := func(key groupKey, task model.Task) {
+		total, ok := totals[key]
+		if !ok {
+			total = &model.EstimateTotal{Tag: key.tag}
+			if groupBy == GroupByStatus {
+				status := key.status
+				total.Status = &status
+			}
+			totals[key] = total
+		}
+		total.Tasks++
+		total.StoryPoints += task.StoryPoints
+		total.OriginalEstimate += task.OriginalEstimate
+		total.RemainingEstimate += task.RemainingEstimate
+	}
+
+	for _, task := range tasks {
+		switch groupBy {
+		case GroupByStatus:
+			add(groupKey{status: task.Status}, task)
+		case GroupByTag:
+			for _, tag := range task.Tags {
+				add(groupKey{tag: tag}, task)
+			}
+		default:
+			add(groupKey{}, task)
+		}
+	}
+
+	result := make([]model.EstimateTotal, 0, len(totals))
+	for _, total := range totals {
+		result = append(result, *total)
+	}
+
+	slices.SortFunc(result, func(a, b model.EstimateTotal) int {
+		var statusA, statusB model.TaskStatus
+		if a.Status != nil {
+			statusA, statusB = *a.Status, *b.Status
+		}
+		return cmp.Or(cmp.Compare(statusA, statusB), cmp.Compare(a.Tag, b.Tag))
+	})
+	return result, nil
+}
+
+// reduceRemainingEstimate subtracts the work logged by actorId from the remaining estimate
+// of the task taskId, without going below zero.
+func (s *TaskService) reduceRemainingEstimate(taskId int, logged model.Duration, actorId int) error {
+	s.taskMutex.Lock()
+	defer s.taskMutex.Unlock()
+
+	task, err := s.findTask(taskId)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to reduce remaining estimate of task %d: %s", taskId, err))
+		return fmt.Errorf("failed to reduce remaining estimate: %w", err)
+	}
+
+	if task.RemainingEstimate == 0 {
+		return nil
+	}
+
+	remaining := max(task.RemainingEstimate-logged, 0)
+	if _, err := s.saveUpdate(task, model.UpdateTask{RemainingEstimate: &remaining}, model.TaskUpdated, actorId); err != nil {
+		return fmt.Errorf("failed to reduce remaining estimate: %w", err)
+	}
+
+	s.log.Info(fmt.Sprintf("Remaining estimate of task %d reduced to %s", taskId, time.Duration(remaining)))
+	return nil
+}
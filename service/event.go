@@ -0,0 +1,187 @@
+package service
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// eventReplaySize is how many of the latest events are kept for subscribers resuming
+	// after a disconnection.
+	eventReplaySize = 1000
+	// eventQueueSize is how many events a subscriber can fall behind before it is dropped.
+	eventQueueSize = 64
+)
+
+// EventSubscription receives the task events matching the filter it was made with.
+type EventSubscription struct {
+	// Replay holds the events published since the event the subscriber resumes after.
+	Replay []model.TaskEvent
+	// Missed is true when the events since the event the subscriber resumes after can no
+	// longer be replayed, the subscriber should then read the tasks again.
+	Missed bool
+	// LastEventId is the id of the latest event published before subscribing, empty when
+	// there is none.
+	LastEventId string
+	// Events receives the events published after subscribing. It is closed when the
+	// subscriber falls more than eventQueueSize events behind, the subscriber can then
+	// subscribe again to resume after the last event it got.
+	Events <-chan model.TaskEvent
+
+	events  chan model.TaskEvent
+	matches func(model.Task) bool
+	broker  *eventBroker
+}
+
+// Close stops the delivery of events to the subscription.
+func (e *EventSubscription) Close() {
+	e.broker.unsubscribe(e)
+}
+
+// publishedEvent is an event along with the task before the change, for updates, so
+// subscribers also see the tasks that stopped matching their filter.
+type publishedEvent struct {
+	event  model.TaskEvent
+	before *model.Task
+}
+
+func (p publishedEvent) matches(matches func(model.Task) bool) bool {
+	return matches(p.event.Task) || (p.before != nil && matches(*p.before))
+}
+
+// eventBroker delivers the task events to the subscriptions and keeps the latest of them
+// in a ring buffer. Event ids are the number of the event prefixed with the start time of
+// the broker, so the ids of an earlier run of the app aren't mistaken for current ones.
+type eventBroker struct {
+	mutex  sync.Mutex
+	epoch  string
+	number int
+	// buffer holds the event numbered n at n % len(buffer).
+	buffer        []publishedEvent
+	subscriptions map[*EventSubscription]struct{}
+}
+
+func newEventBroker(replaySize int) *eventBroker {
+	return &eventBroker{
+		epoch:         strconv.FormatInt(time.Now().UnixNano(), 36),
+		buffer:        make([]publishedEvent, replaySize),
+		subscriptions: make(map[*EventSubscription]struct{}),
+	}
+}
+
+// publish numbers event and sends it to the subscriptions it matches. Subscriptions whose
+// queue is full are closed rather than waited for.
+func (b *eventBroker) publish(event model.TaskEvent, before *model.Task) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	b.number++
+	event.Id = fmt.Sprintf("%s-%d", b.epoch, b.number)
+	published := publishedEvent{event: event, before: before}
+	b.buffer[b.number%len(b.buffer)] = published
+
+	for subscription := range b.subscriptions {
+		if !published.matches(subscription.matches) {
+			continue
+		}
+
+		select {
+		case subscription.events <- event:
+		default:
+			delete(b.subscriptions, subscription)
+			close(subscription.events)
+		}
+	}
+}
+
+// subscribe registers a subscription to the events for which matches is true, replaying
+// the events published after the event lastEventId. An empty lastEventId replays nothing.
+func (b *eventBroker) subscribe(matches func(model.Task) bool, lastEventId string) *EventSubscription {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	events := make(chan model.TaskEvent, eventQueueSize)
+	subscription := &EventSubscription{Events: events, events: events, matches: matches, broker: b}
+	b.subscriptions[subscription] = struct{}{}
+	if b.number > 0 {
+		subscription.LastEventId = fmt.Sprintf("%s-%d", b.epoch, b.number)
+	}
+
+	if lastEventId == "" {
+		return subscription
+	}
+
+	last, ok := b.eventNumber(lastEventId)
+	if !ok || last > b.number || last < b.number-len(b.buffer) {
+		subscription.Missed = true
+		return subscription
+	}
+
+	for n := last + 1; n <= b.number; n++ {
+		if published := b.buffer[n%len(b.buffer)]; published.matches(matches) {
+			subscription.Replay = append(subscription.Replay, published.event)
+		}
+	}
+	return subscription
+}
+
+func (b *eventBroker) unsubscribe(subscription *EventSubscription) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+
+	if _, ok := b.subscriptions[subscription]; ok {
+		delete(b.subscriptions, subscription)
+		close(subscription.events)
+	}
+}
+
+// eventNumber returns the number of the event id, which must come from this broker.
+func (b *eventBroker) eventNumber(id string) (int, bool) {
+	epoch, number, found := strings.Cut(id, "-")
+	if !found || epoch != b.epoch {
+		return 0, false
+	}
+
+	n, err := strconv.Atoi(number)
+	if err != nil || n < 0 {
+		return 0, false
+	}
+	return n, true
+}
+
+// SubscribeEvents subscribes to the events of the tasks matching filter, resuming after
+// the event lastEventId when it isn't empty. The subscription must be closed once done.
+func (s *TaskService) SubscribeEvents(filter model.TaskFilter, lastEventId string) (*EventSubscription, error) {
+	matcher, err := s.newTaskMatcher(filter)
+	if err != nil {
+		return nil, err
+	}
+	return s.events.subscribe(matcher.matches, lastEventId), nil
+}
+
+// publishEvent notifies the subscribers of the change recorded by revision, before is the
+// task before an update.
+func (s *TaskService) publishEvent(revision model.TaskRevision, before model.Task) {
+	event := model.TaskEvent{
+		Type:    revision.Type,
+		ActorId: revision.ActorId,
+		Fields:  revision.Fields,
+		Task:    revision.Task,
+		At:      revision.At,
+	}
+
+	switch revision.Type {
+	case model.TaskUpdated, model.TaskMoved:
+		event.Type = model.TaskUpdated
+		s.events.publish(event, &before)
+	case model.TaskArchived:
+		event.Type = model.TaskDeleted
+		s.events.publish(event, nil)
+	default:
+		s.events.publish(event, nil)
+	}
+}
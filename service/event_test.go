@@ -0,0 +1,87 @@
+package service
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+	"slices"
+	"testing"
+)
+
+func TestEventBrokerSubscribe(t *testing.T) {
+	broker := newEventBroker(3)
+	id := func(n int) string { return fmt.Sprintf("%s-%d", broker.epoch, n) }
+
+	before := model.Task{Id: 1, Tags: []string{"ui"}}
+	broker.publish(model.TaskEvent{Type: model.TaskCreated, Task: model.Task{Id: 1, Tags: []string{"ui"}}}, nil)
+	broker.publish(model.TaskEvent{Type: model.TaskCreated, Task: model.Task{Id: 2}}, nil)
+	broker.publish(model.TaskEvent{Type: model.TaskUpdated, Task: model.Task{Id: 1}}, &before)
+	broker.publish(model.TaskEvent{Type: model.TaskDeleted, Task: model.Task{Id: 2}}, nil)
+
+	all := func(model.Task) bool { return true }
+	ui := func(task model.Task) bool { return slices.Contains(task.Tags, "ui") }
+
+	var testTable = []struct {
+		lastEventId string
+		matches     func(model.Task) bool
+		missed      bool
+		expected    []string
+	}{
+		{"", all, false, nil},
+		{id(2), all, false, []string{id(3), id(4)}},
+		{id(1), all, false, []string{id(2), id(3), id(4)}},
+		{id(4), all, false, nil},
+		// the update took the task out of the filter
+		{id(1), ui, false, []string{id(3)}},
+		// no longer in the buffer
+		{id(0), all, true, nil},
+		{id(5), all, true, nil},
+		{"earlier-2", all, true, nil},
+		{"2", all, true, nil},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%s), Expect: %v %v", testData.lastEventId, testData.missed, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			subscription := broker.subscribe(testData.matches, testData.lastEventId)
+			defer subscription.Close()
+
+			var replayed []string
+			for _, event := range subscription.Replay {
+				replayed = append(replayed, event.Id)
+			}
+			if subscription.Missed != testData.missed || !slices.Equal(replayed, testData.expected) {
+				t.Errorf("with input (%s) got %v %v, but expected %v %v", testData.lastEventId, subscription.Missed, replayed, testData.missed, testData.expected)
+			}
+			if subscription.LastEventId != id(4) {
+				t.Errorf("with input (%s) got last event id %s, but expected %s", testData.lastEventId, subscription.LastEventId, id(4))
+			}
+		})
+	}
+}
+
+func TestEventBrokerPublish(t *testing.T) {
+	broker := newEventBroker(eventReplaySize)
+	subscription := broker.subscribe(func(task model.Task) bool { return task.ProjectId == 1 }, "")
+
+	broker.publish(model.TaskEvent{Type: model.TaskCreated, Task: model.Task{Id: 1, ProjectId: 1}}, nil)
+	broker.publish(model.TaskEvent{Type: model.TaskCreated, Task: model.Task{Id: 2, ProjectId: 2}}, nil)
+	if event := <-subscription.Events; event.Task.Id != 1 {
+		t.Errorf("got event of task %d, but expected task 1", event.Task.Id)
+	}
+
+	// a subscriber falling behind is dropped instead of blocking the publisher
+	for i := 0; i <= eventQueueSize; i++ {
+		broker.publish(model.TaskEvent{Type: model.TaskUpdated, Task: model.Task{Id: 1, ProjectId: 1}}, nil)
+	}
+	received := 0
+	for range subscription.Events {
+		received++
+	}
+	if received != eventQueueSize {
+		t.Errorf("got %d events before the subscription closed, but expected %d", received, eventQueueSize)
+	}
+
+	subscription.Close()
+}
@@ -0,0 +1,81 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go-task-tracker/model"
+	"log/slog"
+	"slices"
+)
+
+func (s *TaskService) GetHistory(taskId int) ([]model.TaskRevision, error) {
+	revisions, err := s.history.GetRevisions(taskId)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to get history of task %d", taskId), slog.Any("err", err))
+		return nil, fmt.Errorf("failed to get task history: %w", err)
+	}
+	return revisions, nil
+}
+
+// recordRevision stores the change of a task from before to after and publishes it to the
+// event subscribers. The change itself is already stored, so failures are logged instead
+// of returned.
+func (s *TaskService) recordRevision(revisionType model.RevisionType, before model.Task, after model.Task, actorId int) {
+	revision := model.TaskRevision{
+		TaskId:  after.Id,
+		Type:    revisionType,
+		ActorId: actorId,
+		Task:    after,
+		At:      model.Now(),
+	}
+
+	if revisionType == model.TaskUpdated || revisionType == model.TaskMoved {
+		revision.Fields = changedFields(before, after)
+	}
+
+	if err := s.history.AddRevision(revision); err != nil {
+		s.log.Error(fmt.Sprintf("failed to record %s revision of task %d: %s", revisionType, after.Id, err))
+	}
+	s.publishEvent(revision, before)
+}
+
+// changedFields returns the JSON names of the fields that differ between before and after,
+// leaving out UpdatedAt which changes on every update.
+func changedFields(before model.Task, after model.Task) []string {
+	beforeFields, errBefore := taskFields(before)
+	afterFields, errAfter := taskFields(after)
+	if errBefore != nil || errAfter != nil {
+		return nil
+	}
+
+	fields := make([]string, 0)
+	for name, value := range afterFields {
+		if !bytes.Equal(value, beforeFields[name]) {
+			fields = append(fields, name)
+		}
+	}
+
+	for name := range beforeFields {
+		if _, ok := afterFields[name]; !ok {
+			fields = append(fields, name)
+		}
+	}
+
+	fields = slices.DeleteFunc(fields, func(name string) bool { return name == "UpdatedAt" })
+	slices.Sort(fields)
+	return fields
+}
+
+func taskFields(task model.Task) (map[string]json.RawMessage, error) {
+	b, err := json.Marshal(&task)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(b, &fields); err != nil {
+		return nil, err
+	}
+	return fields, nil
+}
@@ -0,0 +1,190 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-task-tracker/model"
+	"slices"
+)
+
+// LinkTasks links the task taskId to the task link.TaskId, storing the inverse link on the
+// other task. Adding a link that already exists does nothing.
+func (s *TaskService) LinkTasks(taskId int, link model.LinkTask, actorId int) (model.Task, error) {
+	if !link.Type.Valid() {
+		err := fmt.Errorf("unknown link type %q", link.Type)
+		s.log.Info(err.Error())
+		return model.Task{}, invalidError(err, err.Error())
+	}
+
+	s.taskMutex.Lock()
+	defer s.taskMutex.Unlock()
+
+	task, other, err := s.findLinkedTasks(taskId, link.TaskId)
+	if err != nil {
+		return model.Task{}, err
+	}
+	return s.saveLink(task, other, link.Type, actorId)
+}
+
+// UnlinkTasks removes the link of type link.Type between the task taskId and the task
+// link.TaskId from both tasks. Removing the duplicates link of a task closed as a duplicate
+// clears DuplicateOf but leaves the task closed.
+func (s *TaskService) UnlinkTasks(taskId int, link model.LinkTask, actorId int) error {
+	s.taskMutex.Lock()
+	defer s.taskMutex.Unlock()
+
+	task, other, err := s.findLinkedTasks(taskId, link.TaskId)
+	if err != nil {
+		return err
+	}
+
+	if !slices.Contains(task.Links, model.TaskLink{Type: link.Type, TaskId: other.Id}) {
+		err := fmt.Errorf("task %d has no %s link to task %d", taskId, link.Type, other.Id)
+		s.log.Info(err.Error())
+		return notFoundError(err, "link does not exist")
+	}
+
+	if err := s.removeLink(task, model.TaskLink{Type: link.Type, TaskId: other.Id}, actorId); err != nil {
+		return err
+	}
+	return s.removeLink(other, model.TaskLink{Type: link.Type.Inverse(), TaskId: task.Id}, actorId)
+}
+
+// ResolveAsDuplicate closes the task taskId as a duplicate of the task canonicalId. When
+// canonicalId is itself a duplicate, the task points to the task it duplicates instead.
+func (s *TaskService) ResolveAsDuplicate(taskId int, canonicalId int, actorId int) (model.Task, error) {
+	s.taskMutex.Lock()
+	defer s.taskMutex.Unlock()
+
+	task, canonical, err := s.findLinkedTasks(taskId, canonicalId)
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	for seen := []int{canonical.Id}; canonical.DuplicateOf != 0; seen = append(seen, canonical.Id) {
+		if canonical, err = s.findTask(canonical.DuplicateOf); err != nil {
+			s.log.Error(fmt.Sprintf("failed to find canonical task of task %d: %s", canonicalId, err))
+			return model.Task{}, fmt.Errorf("failed to resolve duplicate: %w", err)
+		}
+
+		if slices.Contains(seen, canonical.Id) {
+			break
+		}
+	}
+
+	if canonical.Id == task.Id {
+		err := fmt.Errorf("task %d is already duplicated by task %d", taskId, canonicalId)
+		s.log.Info(err.Error())
+		return model.Task{}, conflictError(err, "a task can't be a duplicate of its own duplicate")
+	}
+
+	// checked before the link is saved, applyUpdate checks it again
+	if err := s.checkWorkflow(task, model.Done); err != nil {
+		return model.Task{}, err
+	}
+
+	if task, err = s.saveLink(task, canonical, model.Duplicates, actorId); err != nil {
+		return model.Task{}, err
+	}
+
+	done := model.Done
+	resolved, err := s.applyUpdate(task, model.UpdateTask{Status: &done, DuplicateOf: &canonical.Id}, actorId)
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	s.log.Info(fmt.Sprintf("Task %d closed as a duplicate of task %d", taskId, canonical.Id))
+	return resolved, nil
+}
+
+func (s *TaskService) findLinkedTasks(taskId int, otherId int) (model.Task, model.Task, error) {
+	if taskId == otherId {
+		err := errors.New("a task can't be linked to itself")
+		s.log.Info(err.Error())
+		return model.Task{}, model.Task{}, invalidError(err, err.Error())
+	}
+
+	task, err := s.findTask(taskId)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to link task %d: %s", taskId, err))
+		return model.Task{}, model.Task{}, fmt.Errorf("failed to link task: %w", err)
+	}
+
+	other, err := s.findTask(otherId)
+	if err != nil {
+		err = fmt.Errorf("failed to find linked task %d: %w", otherId, err)
+		s.log.Error(err.Error())
+		return model.Task{}, model.Task{}, invalidError(err, "linked task does not exist")
+	}
+	return task, other, nil
+}
+
+// saveLink stores a link of type linkType from task to other and its inverse on other,
+// returning task with the link.
+func (s *TaskService) saveLink(task model.Task, other model.Task, linkType model.LinkType, actorId int) (model.Task, error) {
+	link := model.TaskLink{Type: linkType, TaskId: other.Id}
+	if slices.Contains(task.Links, link) {
+		return task, nil
+	}
+
+	links := append(slices.Clone(task.Links), link)
+	task, err := s.saveUpdate(task, model.UpdateTask{Links: &links}, model.TaskUpdated, actorId)
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	inverse := model.TaskLink{Type: linkType.Inverse(), TaskId: task.Id}
+	if !slices.Contains(other.Links, inverse) {
+		otherLinks := append(slices.Clone(other.Links), inverse)
+		if _, err := s.saveUpdate(other, model.UpdateTask{Links: &otherLinks}, model.TaskUpdated, actorId); err != nil {
+			return model.Task{}, err
+		}
+	}
+	return task, nil
+}
+
+func (s *TaskService) removeLink(task model.Task, link model.TaskLink, actorId int) error {
+	links := slices.DeleteFunc(slices.Clone(task.Links), func(l model.TaskLink) bool { return l == link })
+	update := model.UpdateTask{Links: &links}
+	if link.Type == model.Duplicates && task.DuplicateOf == link.TaskId {
+		noDuplicate := 0
+		update.DuplicateOf = &noDuplicate
+	}
+
+	_, err := s.saveUpdate(task, update, model.TaskUpdated, actorId)
+	return err
+}
+
+// removeLinksTo removes the links other tasks have to task, which is being deleted, and
+// forgets it as the task they duplicate. Every task changed records a single revision,
+// tasks already archived keep their links.
+func (s *TaskService) removeLinksTo(task model.Task, actorId int) error {
+	var unlinked []int
+	for _, link := range task.Links {
+		if slices.Contains(unlinked, link.TaskId) {
+			continue
+		}
+		unlinked = append(unlinked, link.TaskId)
+
+		other, err := s.findTask(link.TaskId)
+		if errors.Is(err, model.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			s.log.Error(fmt.Sprintf("failed to unlink task %d from task %d: %s", link.TaskId, task.Id, err))
+			return fmt.Errorf("failed to unlink task: %w", err)
+		}
+
+		links := slices.DeleteFunc(slices.Clone(other.Links), func(l model.TaskLink) bool { return l.TaskId == task.Id })
+		update := model.UpdateTask{Links: &links}
+		if other.DuplicateOf == task.Id {
+			noDuplicate := 0
+			update.DuplicateOf = &noDuplicate
+		}
+
+		if _, err := s.saveUpdate(other, update, model.TaskUpdated, actorId); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package service
+
+import (
+	"cmp"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go-task-tracker/model"
+)
+
+// taskCursor is the position of a task in a listing: its values for the keys the listing
+// is sorted by. Clients get it as an opaque string.
+type taskCursor struct {
+	Sort   string `json:"s"`
+	Values []any  `json:"v"`
+}
+
+func encodeCursor(keys model.TaskSort, values []any) (string, error) {
+	b, err := json.Marshal(taskCursor{Sort: keys.String(), Values: values})
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal cursor: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
+// decodeCursor returns the values of the cursor, nil for an empty one. It fails when
+// cursor wasn't made for a listing sorted by keys.
+func decodeCursor(cursor string, keys model.TaskSort) ([]any, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	b, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, err
+	}
+
+	var decoded taskCursor
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		return nil, err
+	}
+
+	if decoded.Sort != keys.String() || len(decoded.Values) != len(keys) {
+		return nil, errors.New("cursor of a listing with another sort")
+	}
+	return decoded.Values, nil
+}
+
+// paginate sorts items and cuts out the page following the cursor of page, which
+// validatePage has checked, of at most page.Limit items or defaultPageLimit when it is 0.
+// Being positions rather than offsets, cursors stay valid while tasks are added or removed.
+func paginate(items []model.TaskListItem, sort model.TaskSort, page model.TaskPage) (model.TaskListPage, error) {
+	keys := sortKeys(sort)
+	values := sortTasks(items, sort)
+	after, _ := decodeCursor(page.Cursor, keys)
+
+	start := 0
+	if after != nil {
+		start = len(items)
+		for i, item := range items {
+			if compareSortValues(values[item.Id], after, keys) > 0 {
+				start = i
+				break
+			}
+		}
+	}
+
+	limit := cmp.Or(page.Limit, defaultPageLimit)
+	result := model.TaskListPage{Items: items[start:], Total: len(items)}
+	if len(result.Items) > limit {
+		result.Items = result.Items[:limit]
+
+		var err error
+		if result.Next, err = encodeCursor(keys, values[result.Items[limit-1].Id]); err != nil {
+			return model.TaskListPage{}, err
+		}
+	}
+	return result, nil
+}
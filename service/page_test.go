@@ -0,0 +1,163 @@
+package service
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+	"slices"
+	"testing"
+)
+
+func TestPaginate(t *testing.T) {
+	tasks := []model.Task{
+		{Id: 1, Key: "OPS-10", Status: model.Done, StoryPoints: 3},
+		{Id: 2, Key: "OPS-2", Status: model.TODO, StoryPoints: 5, AssigneeId: 7},
+		{Id: 3, Key: "DEV-1", Status: model.TODO, StoryPoints: 1},
+		{Id: 4, Status: model.InProgress, StoryPoints: 5, AssigneeId: 2},
+		{Id: 5, Key: "OPS-9", Status: model.TODO, StoryPoints: 5},
+	}
+
+	var testTable = []struct {
+		sort     string
+		limit    int
+		expected [][]int
+	}{
+		{"", 2, [][]int{{1, 2}, {3, 4}, {5}}},
+		{"", 0, [][]int{{1, 2, 3, 4, 5}}},
+		{"-storyPoints", 2, [][]int{{2, 4}, {5, 1}, {3}}},
+		{"status,-storyPoints", 3, [][]int{{2, 5, 3}, {4, 1}}},
+		{"assigneeId", 2, [][]int{{4, 2}, {1, 3}, {5}}},
+		{"-assigneeId,-id", 1, [][]int{{2}, {4}, {5}, {3}, {1}}},
+		// keys sort by project and then by number, tasks without a key come last
+		{"key", 2, [][]int{{3, 2}, {5, 1}, {4}}},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%s, %d), Expect: %v", testData.sort, testData.limit, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			var sort model.TaskSort
+			if testData.sort != "" {
+				var err error
+				if sort, err = model.ParseTaskSort(testData.sort); err != nil {
+					t.Fatalf("failed to parse sort: %s", err)
+				}
+			}
+
+			var pages [][]int
+			page := model.TaskPage{Limit: testData.limit}
+			for {
+				if err := validatePage(page, sort); err != nil {
+					t.Fatalf("expected cursor to be valid, got \"%s\"", err)
+				}
+
+				result, err := paginate(listItems(tasks), sort, page)
+				if err != nil {
+					t.Fatalf("expected paginate call to return no errors, got \"%s\"", err)
+				}
+				if result.Total != len(tasks) {
+					t.Errorf("with input (%s, %d) got total %d, but expected %d", testData.sort, testData.limit, result.Total, len(tasks))
+				}
+
+				var ids []int
+				for _, item := range result.Items {
+					ids = append(ids, item.Id)
+				}
+				pages = append(pages, ids)
+
+				if result.Next == "" {
+					break
+				}
+				page.Cursor = result.Next
+			}
+
+			if !slices.EqualFunc(pages, testData.expected, slices.Equal) {
+				t.Errorf("with input (%s, %d) got pages %v, but expected %v", testData.sort, testData.limit, pages, testData.expected)
+			}
+		})
+	}
+}
+
+func TestPaginate_TasksAddedBetweenPages(t *testing.T) {
+	tasks := []model.Task{{Id: 1, StoryPoints: 1}, {Id: 2, StoryPoints: 2}, {Id: 3, StoryPoints: 3}, {Id: 4, StoryPoints: 4}}
+	sort := model.TaskSort{{Field: "storyPoints"}}
+
+	first, err := paginate(listItems(tasks), sort, model.TaskPage{Limit: 2})
+	if err != nil {
+		t.Fatalf("expected paginate call to return no errors, got \"%s\"", err)
+	}
+
+	// a task sorted before the cursor and one after it are added
+	tasks = append(tasks, model.Task{Id: 5, StoryPoints: 0}, model.Task{Id: 6, StoryPoints: 3})
+	second, err := paginate(listItems(tasks), sort, model.TaskPage{Limit: 2, Cursor: first.Next})
+	if err != nil {
+		t.Fatalf("expected paginate call to return no errors, got \"%s\"", err)
+	}
+
+	var ids []int
+	for _, item := range second.Items {
+		ids = append(ids, item.Id)
+	}
+	if !slices.Equal(ids, []int{3, 6}) {
+		t.Errorf("got second page %v, but expected [3 6]", ids)
+	}
+}
+
+func TestPaginate_DefaultLimit(t *testing.T) {
+	tasks := make([]model.Task, 0, defaultPageLimit+1)
+	for id := 1; id <= defaultPageLimit+1; id++ {
+		tasks = append(tasks, model.Task{Id: id})
+	}
+
+	result, err := paginate(listItems(tasks), nil, model.TaskPage{})
+	if err != nil {
+		t.Fatalf("expected paginate call to return no errors, got \"%s\"", err)
+	}
+	if len(result.Items) != defaultPageLimit || result.Next == "" {
+		t.Errorf("got %d tasks and next cursor %q, but expected %d tasks and a next page", len(result.Items), result.Next, defaultPageLimit)
+	}
+}
+
+func TestValidatePage(t *testing.T) {
+	first, err := paginate(listItems([]model.Task{{Id: 1}, {Id: 2}}), nil, model.TaskPage{Limit: 1})
+	if err != nil {
+		t.Fatalf("expected paginate call to return no errors, got \"%s\"", err)
+	}
+	cursor := first.Next
+
+	var testTable = []struct {
+		page     model.TaskPage
+		sort     model.TaskSort
+		expected []FieldError
+	}{
+		{model.TaskPage{Limit: 10, Cursor: cursor}, nil, nil},
+		{model.TaskPage{Limit: -1}, nil, []FieldError{{Field: "limit", Message: "must be between 1 and 1000, or 0 for the default of 50"}}},
+		{model.TaskPage{Limit: 1001}, nil, []FieldError{{Field: "limit", Message: "must be between 1 and 1000, or 0 for the default of 50"}}},
+		{model.TaskPage{Cursor: "not a cursor"}, nil, []FieldError{{Field: "cursor", Message: "is not a cursor of this listing, it may have been made with another sort"}}},
+		{model.TaskPage{Cursor: cursor}, model.TaskSort{{Field: "dueAt"}}, []FieldError{{Field: "cursor", Message: "is not a cursor of this listing, it may have been made with another sort"}}},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%+v, %s), Expect: %v", testData.page, testData.sort, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			var fields []FieldError
+			if err := validatePage(testData.page, testData.sort); err != nil {
+				fields = err.(*ValidationError).Fields
+			}
+
+			if !slices.Equal(fields, testData.expected) {
+				t.Errorf("with input (%+v, %s) got %v, but expected %v", testData.page, testData.sort, fields, testData.expected)
+			}
+		})
+	}
+}
+
+func listItems(tasks []model.Task) []model.TaskListItem {
+	items := make([]model.TaskListItem, 0, len(tasks))
+	for _, task := range tasks {
+		items = append(items, model.TaskListItem{Task: task})
+	}
+	return items
+}
@@ -0,0 +1,284 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-task-tracker/model"
+	"log/slog"
+	"maps"
+	"regexp"
+)
+
+var projectKeyRegexp = regexp.MustCompile(`^[A-Z][A-Z0-9]{1,9}$`)
+
+type ProjectService struct {
+	repository ProjectRepository
+	tasks      TaskRepository
+	users      UserRepository
+	log        slog.Logger
+}
+
+func NewProjectService(repositories Repositories, log *slog.Logger) ProjectService {
+	return ProjectService{
+		repository: repositories.Projects,
+		tasks:      repositories.Tasks,
+		users:      repositories.Users,
+		log:        *log,
+	}
+}
+
+func (s *ProjectService) AddProject(newProject model.CreateProject) (model.Project, error) {
+	if err := validateCreateProject(newProject); err != nil {
+		s.log.Info(fmt.Sprintf("invalid project: %s", err))
+		return model.Project{}, err
+	}
+
+	if !projectKeyRegexp.MatchString(newProject.Key) {
+		err := fmt.Errorf("invalid project key %q", newProject.Key)
+		s.log.Info(err.Error())
+		return model.Project{}, invalidError(err, "project keys are 2 to 10 uppercase letters or digits, starting with a letter")
+	}
+
+	if err := s.checkDefaultAssignee(newProject.DefaultAssigneeId); err != nil {
+		return model.Project{}, err
+	}
+
+	if err := checkFieldDefinitions(newProject.Fields); err != nil {
+		s.log.Info(err.Error())
+		return model.Project{}, err
+	}
+
+	project, err := s.repository.AddProject(model.Project{
+		Key:               newProject.Key,
+		Name:              newProject.Name,
+		Description:       newProject.Description,
+		DefaultAssigneeId: newProject.DefaultAssigneeId,
+		Workflow:          newProject.Workflow,
+		Fields:            newProject.Fields,
+		CreatedAt:         model.Now(),
+		UpdatedAt:         model.Now(),
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to create project: %w", err)
+		s.log.Error(err.Error())
+		return model.Project{}, NewError(err, "error when creating project")
+	}
+	return project, nil
+}
+
+func (s *ProjectService) GetProjects() ([]model.Project, error) {
+	projects, err := s.repository.GetAllProjects()
+	if err != nil {
+		s.log.Error("failed to get projects", slog.Any("err", err))
+		return nil, fmt.Errorf("failed to get projects: %w", err)
+	}
+	return projects, nil
+}
+
+func (s *ProjectService) GetProject(key string) (model.Project, error) {
+	project, err := s.repository.GetProjectByKey(key)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to get project %s", key), slog.Any("err", err))
+		return model.Project{}, fmt.Errorf("failed to get project: %w", err)
+	}
+	return project, nil
+}
+
+func (s *ProjectService) UpdateProject(key string, update model.UpdateProject) (model.Project, error) {
+	if err := validateUpdateProject(update); err != nil {
+		s.log.Info(fmt.Sprintf("invalid update of project %s: %s", key, err))
+		return model.Project{}, err
+	}
+
+	project, err := s.GetProject(key)
+	if err != nil {
+		return model.Project{}, err
+	}
+
+	if update.DefaultAssigneeId != nil {
+		if err := s.checkDefaultAssignee(*update.DefaultAssigneeId); err != nil {
+			return model.Project{}, err
+		}
+	}
+
+	if update.Fields != nil {
+		if err := checkFieldDefinitions(*update.Fields); err != nil {
+			s.log.Info(err.Error())
+			return model.Project{}, err
+		}
+	}
+
+	update.Apply(&project)
+	project.UpdatedAt = model.Now()
+	if err := s.repository.UpdateProject(project); err != nil {
+		s.log.Error(fmt.Sprintf("error when updating project: %s", err))
+		return model.Project{}, fmt.Errorf("failed to update project: %w", err)
+	}
+	return project, nil
+}
+
+// DeleteProject removes an empty project, projects with tasks can't be deleted.
+func (s *ProjectService) DeleteProject(key string) error {
+	project, err := s.GetProject(key)
+	if err != nil {
+		return err
+	}
+
+	tasks, err := s.tasks.GetAllTasks()
+	if err != nil {
+		s.log.Error("failed to get tasks", slog.Any("err", err))
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+
+	for _, task := range tasks {
+		if task.ProjectId == project.Id {
+			err := fmt.Errorf("project %s still has tasks", key)
+			s.log.Info(err.Error())
+			return conflictError(err, "projects with tasks can't be deleted")
+		}
+	}
+
+	if err := s.repository.DeleteProject(project.Id); err != nil {
+		s.log.Error(fmt.Sprintf("failed to delete project %s: %s", key, err))
+		return fmt.Errorf("failed to delete project: %w", err)
+	}
+	return nil
+}
+
+func (s *ProjectService) checkDefaultAssignee(assigneeId int) error {
+	if assigneeId == 0 {
+		return nil
+	}
+
+	if _, err := s.users.GetUser(assigneeId); err != nil {
+		err = fmt.Errorf("failed to find default assignee: %w", err)
+		s.log.Error(err.Error())
+		return invalidError(err, "default assignee does not exist")
+	}
+	return nil
+}
+
+// MoveTask moves the task taskId to the project with key projectKey. The task gets a key
+// in the new project, keeps its id, comments, work logs and history, and remembers its
+// previous keys.
+func (s *TaskService) MoveTask(taskId int, projectKey string, actorId int) (model.Task, error) {
+	project, err := s.projects.GetProjectByKey(projectKey)
+	if err != nil {
+		err = fmt.Errorf("failed to find project: %w", err)
+		s.log.Error(err.Error())
+		return model.Task{}, invalidError(err, "project does not exist")
+	}
+
+	s.taskMutex.Lock()
+	defer s.taskMutex.Unlock()
+
+	task, err := s.findTask(taskId)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to move task %d: %s", taskId, err))
+		return model.Task{}, fmt.Errorf("failed to move task: %w", err)
+	}
+
+	if task.ProjectId == project.Id {
+		return task, nil
+	}
+
+	// custom fields the new project doesn't define, or defines with another type, are dropped
+	stray := strayCustomFields(task, project)
+	kept := maps.Clone(task.CustomFields)
+	maps.DeleteFunc(kept, func(name string, _ any) bool { _, ok := stray[name]; return ok })
+	if err := s.checkRequiredFields(project, kept); err != nil {
+		return model.Task{}, err
+	}
+
+	key, err := s.nextTaskKey(project.Id)
+	if err != nil {
+		s.log.Error(err.Error())
+		return model.Task{}, err
+	}
+
+	previousKeys := task.PreviousKeys
+	if task.Key != "" {
+		previousKeys = append(previousKeys, task.Key)
+	}
+
+	update := model.UpdateTask{
+		ProjectId:    &project.Id,
+		Key:          &key,
+		PreviousKeys: &previousKeys,
+		CustomFields: stray,
+	}
+	moved, err := s.saveUpdate(task, update, model.TaskMoved, actorId)
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	s.log.Info(fmt.Sprintf("Task %d moved to project %s as %s", taskId, projectKey, key))
+	return moved, nil
+}
+
+func (s *TaskService) nextTaskKey(projectId int) (string, error) {
+	project, err := s.projects.GetProject(projectId)
+	if err != nil {
+		return "", fmt.Errorf("failed to find project of task: %w", err)
+	}
+
+	number, err := s.projects.NextTaskNumber(projectId)
+	if err != nil {
+		return "", fmt.Errorf("failed to number task: %w", err)
+	}
+	return fmt.Sprintf("%s-%d", project.Key, number), nil
+}
+
+// resolveProject sets the ProjectId of filter from its project key. The key comes from
+// the query or the path of a listing, so an unknown one is reported as not found.
+func (s *TaskService) resolveProject(filter *model.TaskFilter) error {
+	if filter.Project == "" {
+		return nil
+	}
+
+	project, err := s.projects.GetProjectByKey(filter.Project)
+	if err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			s.log.Info(fmt.Sprintf("project %s does not exist", filter.Project))
+			return notFoundError(err, "project does not exist")
+		}
+		s.log.Error(fmt.Sprintf("failed to find project %s", filter.Project), slog.Any("err", err))
+		return fmt.Errorf("failed to find project: %w", err)
+	}
+	filter.ProjectId = project.Id
+	return nil
+}
+
+// taskProject returns the project of task, the zero project for tasks outside of one.
+func (s *TaskService) taskProject(task model.Task) (model.Project, error) {
+	if task.ProjectId == 0 {
+		return model.Project{}, nil
+	}
+
+	project, err := s.projects.GetProject(task.ProjectId)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to find project of task %d: %s", task.Id, err))
+		return model.Project{}, fmt.Errorf("failed to find project of task: %w", err)
+	}
+	return project, nil
+}
+
+// checkWorkflow fails when the workflow of the project of task doesn't allow moving it to status.
+func (s *TaskService) checkWorkflow(task model.Task, status model.TaskStatus) error {
+	if task.ProjectId == 0 || task.Status == status {
+		return nil
+	}
+
+	project, err := s.projects.GetProject(task.ProjectId)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to find project of task %d: %s", task.Id, err))
+		return fmt.Errorf("failed to check workflow: %w", err)
+	}
+
+	if !project.Allows(task.Status, status) {
+		err := fmt.Errorf("workflow of project %s does not allow moving from %s to %s", project.Key, task.Status, status)
+		s.log.Info(err.Error())
+		return conflictError(err, err.Error())
+	}
+	return nil
+}
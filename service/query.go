@@ -0,0 +1,519 @@
+package service
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"go-task-tracker/model"
+	"go-task-tracker/query"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// queryFields lists the fields task queries can use, besides custom fields written as
+// cf.<name>.
+var queryFields = []string{"id", "key", "description", "status", "tag", "assignee", "reporter", "project", "sprint", "storyPoints", "created", "updated", "due", "started", "completed"}
+
+type taskPredicate func(task model.Task) bool
+
+func matchAll(model.Task) bool { return true }
+
+// taskMatcher matches tasks against a TaskFilter and its query, and holds the index
+// lookups narrowing down the tasks that can match.
+type taskMatcher struct {
+	filter  model.TaskFilter
+	query   taskPredicate
+	lookups []model.TaskLookup
+}
+
+// newTaskMatcher resolves the project of filter and compiles its query.
+func (s *TaskService) newTaskMatcher(filter model.TaskFilter) (taskMatcher, error) {
+	if err := s.resolveProject(&filter); err != nil {
+		return taskMatcher{}, err
+	}
+
+	matcher := taskMatcher{filter: filter, query: matchAll, lookups: filterLookups(filter)}
+	if filter.Query == "" {
+		return matcher, nil
+	}
+
+	expr, err := query.Parse(filter.Query)
+	if err != nil {
+		s.log.Info("invalid task query", slog.String("query", filter.Query), slog.Any("err", err))
+		return taskMatcher{}, invalidError(err, "invalid query: "+err.Error())
+	}
+
+	compiler := queryCompiler{service: s, filter: filter, indexed: make(map[*query.Comparison]model.TaskLookup)}
+	if matcher.query, err = compiler.compile(expr); err != nil {
+		s.log.Info("invalid task query", slog.String("query", filter.Query), slog.Any("err", err))
+		var syntaxErr *query.SyntaxError
+		if errors.As(err, &syntaxErr) {
+			return taskMatcher{}, invalidError(err, "invalid query: "+err.Error())
+		}
+		return taskMatcher{}, err
+	}
+
+	matcher.lookups = append(matcher.lookups, planLookups(expr, compiler.indexed)...)
+	return matcher, nil
+}
+
+func (m taskMatcher) matches(task model.Task) bool {
+	return matchesFilter(task, m.filter) && m.query(task)
+}
+
+// findTasks returns the tasks matching m, reading the tasks selected by its lookups only
+// when it has some.
+func (s *TaskService) findTasks(m taskMatcher) ([]model.Task, error) {
+	var tasks []model.Task
+	var err error
+	if len(m.lookups) > 0 {
+		tasks, err = s.repository.FindTasks(m.lookups)
+	} else {
+		tasks, err = s.repository.GetAllTasks()
+	}
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to get tasks using filters %+v", m.filter), slog.Any("err", err))
+		return nil, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	return slices.DeleteFunc(tasks, func(task model.Task) bool { return !m.matches(task) }), nil
+}
+
+// filterLookups returns the index lookups of the exact fields of filter.
+func filterLookups(filter model.TaskFilter) []model.TaskLookup {
+	var lookups []model.TaskLookup
+	if filter.Status != nil {
+		lookups = append(lookups, model.TaskLookup{Index: model.IndexStatus, Keys: []string{filter.Status.Name()}})
+	}
+
+	if filter.AssigneeId != 0 {
+		lookups = append(lookups, model.TaskLookup{Index: model.IndexAssignee, Keys: []string{strconv.Itoa(filter.AssigneeId)}})
+	}
+
+	if filter.ProjectId != 0 {
+		lookups = append(lookups, model.TaskLookup{Index: model.IndexProject, Keys: []string{strconv.Itoa(filter.ProjectId)}})
+	}
+
+	if filter.SprintId != 0 {
+		lookups = append(lookups, model.TaskLookup{Index: model.IndexSprint, Keys: []string{strconv.Itoa(filter.SprintId)}})
+	}
+
+	if filter.Tag != "" {
+		lookups = append(lookups, model.TaskLookup{Index: model.IndexTag, Keys: []string{filter.Tag}})
+	}
+	return lookups
+}
+
+// planLookups returns the index lookups every task matching expr is selected by. These
+// come from the indexed comparisons expr is a conjunction of, and from disjunctions of
+// comparisons on the same index. Other parts of expr are left to the task predicate.
+func planLookups(expr query.Expr, indexed map[*query.Comparison]model.TaskLookup) []model.TaskLookup {
+	switch e := expr.(type) {
+	case *query.And:
+		return append(planLookups(e.Left, indexed), planLookups(e.Right, indexed)...)
+
+	case *query.Or:
+		left := planLookups(e.Left, indexed)
+		right := planLookups(e.Right, indexed)
+		if len(left) == 1 && len(right) == 1 && left[0].Index == right[0].Index {
+			keys := slices.Concat(left[0].Keys, right[0].Keys)
+			return []model.TaskLookup{{Index: left[0].Index, Keys: keys}}
+		}
+
+	case *query.Comparison:
+		if lookup, ok := indexed[e]; ok {
+			return []model.TaskLookup{lookup}
+		}
+	}
+	return nil
+}
+
+// queryCompiler turns a parsed query into a task predicate, recording the index lookup
+// of the comparisons an index can answer.
+type queryCompiler struct {
+	service *TaskService
+	filter  model.TaskFilter
+	indexed map[*query.Comparison]model.TaskLookup
+}
+
+func (c queryCompiler) compile(expr query.Expr) (taskPredicate, error) {
+	switch e := expr.(type) {
+	case *query.And:
+		left, right, err := c.compileBoth(e.Left, e.Right)
+		if err != nil {
+			return nil, err
+		}
+		return func(task model.Task) bool { return left(task) && right(task) }, nil
+
+	case *query.Or:
+		left, right, err := c.compileBoth(e.Left, e.Right)
+		if err != nil {
+			return nil, err
+		}
+		return func(task model.Task) bool { return left(task) || right(task) }, nil
+
+	case *query.Not:
+		inner, err := c.compile(e.Expr)
+		if err != nil {
+			return nil, err
+		}
+		return func(task model.Task) bool { return !inner(task) }, nil
+
+	case *query.Text:
+		text := strings.ToLower(e.Value)
+		return func(task model.Task) bool { return strings.Contains(strings.ToLower(task.Description), text) }, nil
+
+	case *query.Comparison:
+		if e.Op == query.NotEqual {
+			equal, err := c.comparison(&query.Comparison{Field: e.Field, Op: query.Equal, Value: e.Value, Pos: e.Pos})
+			if err != nil {
+				return nil, err
+			}
+			return func(task model.Task) bool { return !equal(task) }, nil
+		}
+		return c.comparison(e)
+	}
+	return nil, fmt.Errorf("unknown query expression %T", expr)
+}
+
+func (c queryCompiler) compileBoth(left query.Expr, right query.Expr) (taskPredicate, taskPredicate, error) {
+	leftPredicate, err := c.compile(left)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	rightPredicate, err := c.compile(right)
+	if err != nil {
+		return nil, nil, err
+	}
+	return leftPredicate, rightPredicate, nil
+}
+
+// comparison compiles e, whose operator is anything but NotEqual.
+func (c queryCompiler) comparison(e *query.Comparison) (taskPredicate, error) {
+	if name, ok := strings.CutPrefix(e.Field, "cf."); ok {
+		return c.customFieldComparison(e, name)
+	}
+
+	switch e.Field {
+	case "id":
+		return c.intComparison(e, func(task model.Task) int { return task.Id })
+
+	case "storyPoints":
+		return c.intComparison(e, func(task model.Task) int { return task.StoryPoints })
+
+	case "status":
+		if err := c.checkOperator(e, query.Equal); err != nil {
+			return nil, err
+		}
+
+		status, err := model.ParseTaskStatus(e.Value)
+		if err != nil {
+			return nil, c.errorAt(e, err.Error())
+		}
+		c.index(e, model.IndexStatus, status.Name())
+		return func(task model.Task) bool { return task.Status == status }, nil
+
+	case "tag":
+		if err := c.checkOperator(e, query.Equal); err != nil {
+			return nil, err
+		}
+
+		tag := strings.TrimSpace(e.Value)
+		c.index(e, model.IndexTag, tag)
+		return func(task model.Task) bool { return slices.Contains(task.Tags, tag) }, nil
+
+	case "assignee":
+		id, err := c.userId(e)
+		if err != nil {
+			return nil, err
+		}
+		c.index(e, model.IndexAssignee, strconv.Itoa(id))
+		return func(task model.Task) bool { return task.AssigneeId == id }, nil
+
+	case "reporter":
+		id, err := c.userId(e)
+		if err != nil {
+			return nil, err
+		}
+		return func(task model.Task) bool { return task.ReporterId == id }, nil
+
+	case "sprint":
+		id, err := c.optionalId(e)
+		if err != nil {
+			return nil, err
+		}
+		c.index(e, model.IndexSprint, strconv.Itoa(id))
+		return func(task model.Task) bool { return task.SprintId == id }, nil
+
+	case "project":
+		id, err := c.projectId(e)
+		if err != nil {
+			return nil, err
+		}
+		c.index(e, model.IndexProject, strconv.Itoa(id))
+		return func(task model.Task) bool { return task.ProjectId == id }, nil
+
+	case "key":
+		if err := c.checkOperator(e, query.Equal, query.Contains); err != nil {
+			return nil, err
+		}
+
+		key := strings.ToUpper(e.Value)
+		if e.Op == query.Contains {
+			return func(task model.Task) bool { return strings.Contains(task.Key, key) }, nil
+		}
+		return func(task model.Task) bool { return task.Key == key || slices.Contains(task.PreviousKeys, key) }, nil
+
+	case "description":
+		if err := c.checkOperator(e, query.Equal, query.Contains); err != nil {
+			return nil, err
+		}
+
+		text := strings.ToLower(e.Value)
+		if e.Op == query.Contains {
+			return func(task model.Task) bool { return strings.Contains(strings.ToLower(task.Description), text) }, nil
+		}
+		return func(task model.Task) bool { return strings.ToLower(task.Description) == text }, nil
+
+	case "created":
+		return c.timeComparison(e, func(task model.Task) *model.DateTime { return &task.CreatedAt })
+
+	case "updated":
+		return c.timeComparison(e, func(task model.Task) *model.DateTime { return &task.UpdatedAt })
+
+	case "due":
+		return c.timeComparison(e, func(task model.Task) *model.DateTime { return task.DueAt })
+
+	case "started":
+		return c.timeComparison(e, func(task model.Task) *model.DateTime { return task.StartedAt })
+
+	case "completed":
+		return c.timeComparison(e, func(task model.Task) *model.DateTime { return task.CompletedAt })
+	}
+
+	if suggestion, ok := closestWord(e.Field, queryFields); ok {
+		return nil, c.errorAt(e, fmt.Sprintf("unknown field %q, did you mean %q", e.Field, suggestion))
+	}
+	return nil, c.errorAt(e, fmt.Sprintf("unknown field %q, expected one of %s or cf.<name>", e.Field, strings.Join(queryFields, ", ")))
+}
+
+func (c queryCompiler) intComparison(e *query.Comparison, value func(model.Task) int) (taskPredicate, error) {
+	if err := c.checkOperator(e, query.Equal, query.Greater, query.GreaterOrEqual, query.Less, query.LessOrEqual); err != nil {
+		return nil, err
+	}
+
+	want, err := strconv.Atoi(e.Value)
+	if err != nil {
+		return nil, c.errorAt(e, fmt.Sprintf("invalid value %q for %s, expected a whole number", e.Value, e.Field))
+	}
+	return func(task model.Task) bool { return satisfies(e.Op, cmp.Compare(value(task), want)) }, nil
+}
+
+// timeComparison compiles a comparison with a date, which stands for the whole day in the
+// time zone of the filter, or with an RFC 3339 time. Tasks without the time never match.
+func (c queryCompiler) timeComparison(e *query.Comparison, value func(model.Task) *model.DateTime) (taskPredicate, error) {
+	if err := c.checkOperator(e, query.Equal, query.Greater, query.GreaterOrEqual, query.Less, query.LessOrEqual); err != nil {
+		return nil, err
+	}
+
+	location := c.filter.Location
+	if location == nil {
+		location = time.UTC
+	}
+
+	// times are compared with the interval [start, end) the value stands for
+	var start, end time.Time
+	if day, err := time.ParseInLocation(time.DateOnly, e.Value, location); err == nil {
+		start, end = day, day.AddDate(0, 0, 1)
+	} else if instant, err := time.Parse(time.RFC3339, e.Value); err == nil {
+		start, end = instant, instant.Add(time.Nanosecond)
+	} else {
+		return nil, c.errorAt(e, fmt.Sprintf("invalid value %q for %s, expected a date such as 2026-01-31 or an RFC 3339 time", e.Value, e.Field))
+	}
+
+	return func(task model.Task) bool {
+		dateTime := value(task)
+		if dateTime == nil {
+			return false
+		}
+
+		t := time.Time(*dateTime)
+		order := 0
+		if t.Before(start) {
+			order = -1
+		} else if !t.Before(end) {
+			order = 1
+		}
+		return satisfies(e.Op, order)
+	}, nil
+}
+
+// customFieldComparison compiles a comparison with the custom field name. Values are
+// compared as numbers when both are numbers and as text otherwise, tasks without the
+// field never match.
+func (c queryCompiler) customFieldComparison(e *query.Comparison, name string) (taskPredicate, error) {
+	if e.Op == query.Contains {
+		text := strings.ToLower(e.Value)
+		return func(task model.Task) bool {
+			value, ok := task.CustomFields[name]
+			return ok && strings.Contains(strings.ToLower(model.FormatFieldValue(value)), text)
+		}, nil
+	}
+
+	if e.Op == query.Equal {
+		return func(task model.Task) bool {
+			value, ok := task.CustomFields[name]
+			return ok && model.FormatFieldValue(value) == e.Value
+		}, nil
+	}
+
+	var want any = e.Value
+	if number, err := strconv.ParseFloat(e.Value, 64); err == nil {
+		want = number
+	}
+	return func(task model.Task) bool {
+		value, ok := task.CustomFields[name]
+		return ok && satisfies(e.Op, model.CompareFieldValues(value, want))
+	}, nil
+}
+
+// userId reads the user id of e, none standing for no user and me for the user of
+// the filter.
+func (c queryCompiler) userId(e *query.Comparison) (int, error) {
+	if e.Value != "me" {
+		return c.optionalId(e)
+	}
+
+	if err := c.checkOperator(e, query.Equal); err != nil {
+		return 0, err
+	}
+
+	if c.filter.UserId == 0 {
+		return 0, c.errorAt(e, fmt.Sprintf("%s:me requires an authenticated user", e.Field))
+	}
+	return c.filter.UserId, nil
+}
+
+// optionalId reads the id of e, none standing for 0.
+func (c queryCompiler) optionalId(e *query.Comparison) (int, error) {
+	if err := c.checkOperator(e, query.Equal); err != nil {
+		return 0, err
+	}
+
+	if e.Value == "none" {
+		return 0, nil
+	}
+
+	id, err := strconv.Atoi(e.Value)
+	if err != nil || id <= 0 {
+		return 0, c.errorAt(e, fmt.Sprintf("invalid value %q for %s, expected an id or none", e.Value, e.Field))
+	}
+	return id, nil
+}
+
+// projectId reads the project key of e, none standing for tasks outside of a project.
+func (c queryCompiler) projectId(e *query.Comparison) (int, error) {
+	if err := c.checkOperator(e, query.Equal); err != nil {
+		return 0, err
+	}
+
+	if e.Value == "none" {
+		return 0, nil
+	}
+
+	project, err := c.service.projects.GetProjectByKey(strings.ToUpper(e.Value))
+	if errors.Is(err, model.ErrNotFound) {
+		return 0, c.errorAt(e, fmt.Sprintf("unknown project %q", e.Value))
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to find project %s: %w", e.Value, err)
+	}
+	return project.Id, nil
+}
+
+// checkOperator fails when the operator of e is none of allowed. The NotEqual operator
+// is allowed along with Equal.
+func (c queryCompiler) checkOperator(e *query.Comparison, allowed ...query.Operator) error {
+	if slices.Contains(allowed, e.Op) {
+		return nil
+	}
+
+	operators := make([]string, 0, len(allowed)+1)
+	for _, op := range allowed {
+		operators = append(operators, fmt.Sprintf("%q", op))
+		if op == query.Equal {
+			operators = append(operators, fmt.Sprintf("%q", query.NotEqual))
+		}
+	}
+	last := len(operators) - 1
+	if last > 0 {
+		operators = append(operators[:last-1], operators[last-1]+" or "+operators[last])
+	}
+	return c.errorAt(e, fmt.Sprintf("operator %q can't be used with %s, use %s", e.Op, e.Field, strings.Join(operators, ", ")))
+}
+
+// index records that the tasks matching e are the ones found under key in index.
+func (c queryCompiler) index(e *query.Comparison, index model.TaskIndex, key string) {
+	c.indexed[e] = model.TaskLookup{Index: index, Keys: []string{key}}
+}
+
+func (c queryCompiler) errorAt(e *query.Comparison, msg string) error {
+	return &query.SyntaxError{Query: c.filter.Query, Pos: e.Pos, Msg: msg}
+}
+
+// satisfies reports whether order, a task value compared with the value of a query,
+// satisfies op.
+func satisfies(op query.Operator, order int) bool {
+	switch op {
+	case query.Equal:
+		return order == 0
+	case query.Greater:
+		return order > 0
+	case query.GreaterOrEqual:
+		return order >= 0
+	case query.Less:
+		return order < 0
+	case query.LessOrEqual:
+		return order <= 0
+	}
+	return false
+}
+
+// closestWord returns the word of candidates closest to word, when it is at most two
+// edits away.
+func closestWord(word string, candidates []string) (string, bool) {
+	best, bestDistance := "", 3
+	for _, candidate := range candidates {
+		if distance := editDistance(strings.ToLower(word), strings.ToLower(candidate)); distance < bestDistance {
+			best, bestDistance = candidate, distance
+		}
+	}
+	return best, best != ""
+}
+
+// editDistance is the Levenshtein distance between a and b.
+func editDistance(a string, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	previous := make([]int, len(rb)+1)
+	current := make([]int, len(rb)+1)
+	for j := range previous {
+		previous[j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		current[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			current[j] = min(previous[j]+1, current[j-1]+1, previous[j-1]+cost)
+		}
+		previous, current = current, previous
+	}
+	return previous[len(rb)]
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+	"go-task-tracker/query"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestCompileQuery(t *testing.T) {
+	day := func(d int) *model.DateTime {
+		dateTime := model.DateTime(time.Date(2026, 1, d, 12, 0, 0, 0, time.UTC))
+		return &dateTime
+	}
+
+	tasks := []model.Task{
+		{Id: 1, Description: "Flaky login test", Status: model.TODO, Tags: []string{"backend"}, AssigneeId: 7, CreatedAt: *day(1)},
+		{Id: 2, Description: "Write docs", Status: model.InProgress, Tags: []string{"docs"}, CreatedAt: *day(2), DueAt: day(10)},
+		{Id: 3, Description: "Fix api", Status: model.Done, Tags: []string{"backend"}, StoryPoints: 5, CreatedAt: *day(3),
+			CustomFields: map[string]any{"severity": float64(2)}},
+	}
+
+	var testTable = []struct {
+		query    string
+		expected []int
+		lookups  string
+	}{
+		{`status:todo`, []int{1}, `[{status [todo]}]`},
+		{`status!=todo`, []int{2, 3}, `[]`},
+		{`tag:backend AND status:done`, []int{3}, `[{tag [backend]} {status [done]}]`},
+		{`tag:backend OR tag:docs`, []int{1, 2, 3}, `[{tag [backend docs]}]`},
+		{`tag:backend OR status:done`, []int{1, 3}, `[]`},
+		{`-tag:backend`, []int{2}, `[]`},
+		{`flaky`, []int{1}, `[]`},
+		{`description~"WRITE"`, []int{2}, `[]`},
+		{`assignee:7`, []int{1}, `[{assignee [7]}]`},
+		{`assignee:none`, []int{2, 3}, `[{assignee [0]}]`},
+		{`assignee:me`, []int{1}, `[{assignee [7]}]`},
+		{`storyPoints>=5`, []int{3}, `[]`},
+		{`created>2026-01-01`, []int{2, 3}, `[]`},
+		{`created:2026-01-02`, []int{2}, `[]`},
+		{`created<=2026-01-02`, []int{1, 2}, `[]`},
+		{`due<2026-02-01`, []int{2}, `[]`},
+		{`cf.severity>1`, []int{3}, `[]`},
+		{`status:todo OR (tag:backend AND storyPoints>1)`, []int{1, 3}, `[]`},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%s), Expect: %v", testData.query, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			expr, err := query.Parse(testData.query)
+			if err != nil {
+				t.Fatalf("failed to parse query: %s", err)
+			}
+
+			compiler := queryCompiler{
+				filter:  model.TaskFilter{Query: testData.query, UserId: 7},
+				indexed: make(map[*query.Comparison]model.TaskLookup),
+			}
+			predicate, err := compiler.compile(expr)
+			if err != nil {
+				t.Fatalf("expected compile call to return no errors, got \"%s\"", err)
+			}
+
+			var matched []int
+			for _, task := range tasks {
+				if predicate(task) {
+					matched = append(matched, task.Id)
+				}
+			}
+			if !slices.Equal(matched, testData.expected) {
+				t.Errorf("with input (%s) got tasks %v, but expected %v", testData.query, matched, testData.expected)
+			}
+
+			if lookups := fmt.Sprint(planLookups(expr, compiler.indexed)); lookups != testData.lookups {
+				t.Errorf("with input (%s) got lookups %s, but expected %s", testData.query, lookups, testData.lookups)
+			}
+		})
+	}
+}
+
+func TestCompileQuery_Errors(t *testing.T) {
+
+	var testTable = []struct {
+		query    string
+		expected string
+	}{
+		{`stauts:todo`, `column 1: unknown field "stauts", did you mean "status"`},
+		{`status>todo`, `column 1: operator ">" can't be used with status, use ":" or "!="`},
+		{`status:later`, `column 1: invalid status "later", expected one of todo, in_progress, done`},
+		{`tag:a AND created>yesterday`, `column 11: invalid value "yesterday" for created, expected a date such as 2026-01-31 or an RFC 3339 time`},
+		{`assignee:me`, `column 1: assignee:me requires an authenticated user`},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%s), Expect: %s", testData.query, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			expr, err := query.Parse(testData.query)
+			if err != nil {
+				t.Fatalf("failed to parse query: %s", err)
+			}
+
+			compiler := queryCompiler{filter: model.TaskFilter{Query: testData.query}, indexed: make(map[*query.Comparison]model.TaskLookup)}
+			if _, err = compiler.compile(expr); err == nil || err.Error() != testData.expected {
+				t.Errorf("with input (%s) got error \"%v\", but expected \"%s\"", testData.query, err, testData.expected)
+			}
+		})
+	}
+}
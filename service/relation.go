@@ -0,0 +1,59 @@
+package service
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+	"log/slog"
+	"slices"
+)
+
+// GetTaskRelations returns the resources of include related to each of tasks, by task id.
+// Each kind of resource is read once for all the tasks.
+func (s *TaskService) GetTaskRelations(tasks []model.Task, include []model.TaskInclude) (map[int]model.TaskRelations, error) {
+	relations := make(map[int]model.TaskRelations, len(tasks))
+	for _, task := range tasks {
+		relations[task.Id] = model.TaskRelations{}
+	}
+
+	if slices.Contains(include, model.IncludeComments) {
+		comments, err := s.comments.GetAllComments()
+		if err != nil {
+			s.log.Error("failed to get comments to include", slog.Any("err", err))
+			return nil, fmt.Errorf("failed to get task comments: %w", err)
+		}
+
+		for id, relation := range relations {
+			relation.Comments = make([]model.Comment, 0)
+			relations[id] = relation
+		}
+		for _, comment := range comments {
+			if relation, ok := relations[comment.TaskId]; ok {
+				relation.Comments = append(relation.Comments, comment)
+				relations[comment.TaskId] = relation
+			}
+		}
+	}
+
+	if slices.Contains(include, model.IncludeAssignee) {
+		users, err := s.users.GetAllUsers()
+		if err != nil {
+			s.log.Error("failed to get users to include", slog.Any("err", err))
+			return nil, fmt.Errorf("failed to get assignees: %w", err)
+		}
+
+		usersById := make(map[int]model.User, len(users))
+		for _, user := range users {
+			user.TokenHash = ""
+			usersById[user.Id] = user
+		}
+
+		for _, task := range tasks {
+			if assignee, ok := usersById[task.AssigneeId]; ok {
+				relation := relations[task.Id]
+				relation.Assignee = &assignee
+				relations[task.Id] = relation
+			}
+		}
+	}
+	return relations, nil
+}
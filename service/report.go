@@ -0,0 +1,133 @@
+package service
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+	"log/slog"
+	"time"
+)
+
+// GetBurndown returns the tasks matching filter left to do at the end of each day from
+// from and before to, see replayDays.
+func (s *TaskService) GetBurndown(filter model.TaskFilter, from time.Time, to time.Time) ([]model.BurndownPoint, error) {
+	points := make([]model.BurndownPoint, 0)
+	err := s.replayDays(filter, from, to, func(date string, tasks []model.Task) {
+		point := model.BurndownPoint{Date: date}
+		for _, task := range tasks {
+			if task.Status != model.Done {
+				point.Tasks++
+				point.StoryPoints += task.StoryPoints
+			}
+		}
+		points = append(points, point)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// GetCumulativeFlow counts the tasks matching filter in each status at the end of each day
+// from from and before to, see replayDays.
+func (s *TaskService) GetCumulativeFlow(filter model.TaskFilter, from time.Time, to time.Time) ([]model.FlowPoint, error) {
+	points := make([]model.FlowPoint, 0)
+	err := s.replayDays(filter, from, to, func(date string, tasks []model.Task) {
+		point := model.FlowPoint{Date: date}
+		for _, task := range tasks {
+			switch task.Status {
+			case model.TODO:
+				point.Todo++
+			case model.InProgress:
+				point.InProgress++
+			case model.Done:
+				point.Done++
+			}
+		}
+		points = append(points, point)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return points, nil
+}
+
+// replayDays rebuilds the tasks from their history and calls visit with the tasks matching
+// filter at the end of each day from from and before to, as they were then. Days are in
+// filter.Location and end at the latest now, later days are left out. Zero dates default
+// to the dates of the sprint of filter, if any, and otherwise to the last 30 days. Tasks
+// stay after being archived, and tasks from before the history was recorded are missing.
+func (s *TaskService) replayDays(filter model.TaskFilter, from time.Time, to time.Time, visit func(date string, tasks []model.Task)) error {
+	location := time.UTC
+	if filter.Location != nil {
+		location = filter.Location
+	}
+
+	if filter.SprintId != 0 && (from.IsZero() || to.IsZero()) {
+		sprint, err := s.sprints.GetSprint(filter.SprintId)
+		if err != nil {
+			err = fmt.Errorf("failed to find sprint: %w", err)
+			s.log.Error(err.Error())
+			return invalidError(err, "sprint does not exist")
+		}
+
+		if from.IsZero() {
+			from = startOfDay(time.Time(sprint.StartAt).In(location))
+		}
+		if to.IsZero() {
+			to = startOfDay(time.Time(sprint.EndAt).In(location)).AddDate(0, 0, 1)
+		}
+	}
+
+	from, to = statsPeriod(IntervalDay, from, to, location)
+	days := statsIntervals(IntervalDay, from, to)
+	if len(days) == 0 || len(days) > maxStatsIntervals {
+		err := fmt.Errorf("invalid period from %s to %s with %d days", from, to, len(days))
+		s.log.Info(err.Error())
+		return invalidError(err, fmt.Sprintf("the period must end after it starts and span at most %d days", maxStatsIntervals))
+	}
+
+	matcher, err := s.newTaskMatcher(filter)
+	if err != nil {
+		return err
+	}
+
+	revisions, err := s.history.GetAllRevisions()
+	if err != nil {
+		s.log.Error("failed to get task history for report", slog.Any("err", err))
+		return fmt.Errorf("failed to get report: %w", err)
+	}
+
+	replayRevisions(revisions, days, time.Now(), matcher.matches, visit)
+	return nil
+}
+
+// replayRevisions applies revisions, in the order they were recorded which is also the
+// order of their times, up to the end of each of days that started by now, and calls
+// visit with the tasks for which matches is true at that point.
+func replayRevisions(revisions []model.TaskRevision, days []time.Time, now time.Time, matches func(model.Task) bool, visit func(date string, tasks []model.Task)) {
+	tasks := make(map[int]model.Task)
+	next := 0
+	for _, day := range days {
+		if day.After(now) {
+			break
+		}
+
+		end := day.AddDate(0, 0, 1)
+		for ; next < len(revisions) && time.Time(revisions[next].At).Before(end); next++ {
+			revision := revisions[next]
+			if revision.Type == model.TaskDeleted {
+				delete(tasks, revision.TaskId)
+			} else {
+				tasks[revision.TaskId] = revision.Task
+			}
+		}
+
+		matching := make([]model.Task, 0)
+		for _, task := range tasks {
+			if matches(task) {
+				matching = append(matching, task)
+			}
+		}
+		visit(day.Format(time.DateOnly), matching)
+	}
+}
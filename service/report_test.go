@@ -0,0 +1,67 @@
+package service
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReplayRevisions(t *testing.T) {
+	at := func(value string) model.DateTime {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatalf("failed to parse time: %s", err)
+		}
+		return model.DateTime(parsed)
+	}
+
+	revisions := []model.TaskRevision{
+		{TaskId: 1, Type: model.TaskCreated, Task: model.Task{Id: 1, Status: model.TODO, ProjectId: 1}, At: at("2026-03-01T09:00:00Z")},
+		{TaskId: 2, Type: model.TaskCreated, Task: model.Task{Id: 2, Status: model.TODO}, At: at("2026-03-01T10:00:00Z")},
+		{TaskId: 1, Type: model.TaskUpdated, Task: model.Task{Id: 1, Status: model.InProgress, ProjectId: 1}, At: at("2026-03-02T09:00:00Z")},
+		{TaskId: 2, Type: model.TaskDeleted, Task: model.Task{Id: 2, Status: model.TODO}, At: at("2026-03-02T23:59:00Z")},
+		{TaskId: 1, Type: model.TaskUpdated, Task: model.Task{Id: 1, Status: model.Done, ProjectId: 1}, At: at("2026-03-04T09:00:00Z")},
+	}
+
+	all := func(model.Task) bool { return true }
+	project := func(task model.Task) bool { return task.ProjectId == 1 }
+
+	var testTable = []struct {
+		from     string
+		to       string
+		now      string
+		matches  func(model.Task) bool
+		expected []string
+	}{
+		{"2026-02-28", "2026-03-05", "2026-03-10T00:00:00Z", all, []string{"2026-02-28:", "2026-03-01:1,2", "2026-03-02:1", "2026-03-03:1", "2026-03-04:1"}},
+		{"2026-03-01", "2026-03-03", "2026-03-10T00:00:00Z", project, []string{"2026-03-01:1", "2026-03-02:1"}},
+		{"2026-03-01", "2026-03-05", "2026-03-02T12:00:00Z", all, []string{"2026-03-01:1,2", "2026-03-02:1"}},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%s, %s, %s), Expect: %v", testData.from, testData.to, testData.now, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			from, _ := time.Parse(time.DateOnly, testData.from)
+			to, _ := time.Parse(time.DateOnly, testData.to)
+			days := statsIntervals(IntervalDay, from, to)
+
+			var answer []string
+			replayRevisions(revisions, days, time.Time(at(testData.now)), testData.matches, func(date string, tasks []model.Task) {
+				ids := make([]string, 0, len(tasks))
+				for _, task := range tasks {
+					ids = append(ids, fmt.Sprint(task.Id))
+				}
+				slices.Sort(ids)
+				answer = append(answer, date+":"+strings.Join(ids, ","))
+			})
+			if !slices.Equal(answer, testData.expected) {
+				t.Errorf("with input (%s, %s, %s) got %v, but expected %v", testData.from, testData.to, testData.now, answer, testData.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package service
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+	"go-task-tracker/search"
+	"log/slog"
+)
+
+const (
+	defaultSearchLimit = 20
+
+	// names of the fields of the search documents
+	descriptionField = "description"
+	commentField     = "comment"
+)
+
+// Search returns the tasks whose description or comments hold the words of query, best
+// matches first and at most limit of them, 0 meaning defaultSearchLimit. Words also match
+// the longer words they start, as "log" matches "login".
+func (s *TaskService) Search(query string, limit int) ([]model.SearchResult, error) {
+	if err := validateSearch(query, limit); err != nil {
+		s.log.Info(fmt.Sprintf("invalid search: %s", err))
+		return nil, err
+	}
+
+	if limit == 0 {
+		limit = defaultSearchLimit
+	}
+
+	results := make([]model.SearchResult, 0)
+	for _, hit := range s.searchIndex.Search(query, limit) {
+		task, err := s.repository.GetTask(hit.Id)
+		if err != nil {
+			// the task was removed since the search
+			s.log.Info(fmt.Sprintf("failed to get task %d found by search: %s", hit.Id, err))
+			continue
+		}
+
+		snippets := make([]model.SearchSnippet, 0, len(hit.Snippets))
+		for _, snippet := range hit.Snippets {
+			snippets = append(snippets, model.SearchSnippet{Field: snippet.Name, CommentId: snippet.Id, Text: snippet.Text})
+		}
+		results = append(results, model.SearchResult{Task: task, Score: hit.Score, Snippets: snippets})
+	}
+	return results, nil
+}
+
+// RebuildSearchIndex indexes every task and comment again, replacing the search index.
+func (s *TaskService) RebuildSearchIndex() error {
+	tasks, err := s.repository.GetAllTasks()
+	if err != nil {
+		s.log.Error("failed to get tasks to index", slog.Any("err", err))
+		return fmt.Errorf("failed to rebuild search index: %w", err)
+	}
+
+	comments, err := s.comments.GetAllComments()
+	if err != nil {
+		s.log.Error("failed to get comments to index", slog.Any("err", err))
+		return fmt.Errorf("failed to rebuild search index: %w", err)
+	}
+
+	taskComments := make(map[int][]model.Comment)
+	for _, comment := range comments {
+		taskComments[comment.TaskId] = append(taskComments[comment.TaskId], comment)
+	}
+
+	s.searchIndex.Reset()
+	for _, task := range tasks {
+		s.searchIndex.Put(searchDocument(task, taskComments[task.Id]))
+	}
+	s.log.Info(fmt.Sprintf("Indexed %d tasks and %d comments for search", len(tasks), len(comments)))
+	return nil
+}
+
+// indexTask puts task along with its comments in the search index. The task itself is
+// already stored, so failures are logged instead of returned.
+func (s *TaskService) indexTask(task model.Task) {
+	comments, err := s.comments.GetComments(task.Id)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to index comments of task %d: %s", task.Id, err))
+	}
+	s.searchIndex.Put(searchDocument(task, comments))
+}
+
+// reindexTask puts the task taskId in the search index again, after a change to its comments.
+func (s *TaskService) reindexTask(taskId int) {
+	task, err := s.repository.GetTask(taskId)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to index task %d: %s", taskId, err))
+		return
+	}
+	s.indexTask(task)
+}
+
+func searchDocument(task model.Task, comments []model.Comment) search.Document {
+	fields := make([]search.Field, 0, len(comments)+1)
+	fields = append(fields, search.Field{Name: descriptionField, Text: task.Description})
+	for _, comment := range comments {
+		fields = append(fields, search.Field{Name: commentField, Id: comment.Id, Text: comment.Body})
+	}
+	return search.Document{Id: task.Id, Fields: fields}
+}
@@ -1,24 +1,168 @@
 package service
 
 import (
+	"errors"
 	"fmt"
 	"go-task-tracker/model"
+	"go-task-tracker/search"
+	"io"
 	"log/slog"
+	"os"
+	"slices"
+	"strings"
+	"sync"
 	"time"
 )
 
 type TaskRepository interface {
-	AddTask(task model.Task) error
+	AddTask(task model.Task) (model.Task, error)
 
-	UpdateTask(taskId int, updatedTask model.UpdateTask) error
+	UpdateTask(taskId int, updatedTask model.UpdateTask) (model.Task, error)
 
 	GetAllTasks() ([]model.Task, error)
 
+	GetTask(taskId int) (model.Task, error)
+
+	// FindTasks returns the tasks selected by every lookup, in id order.
+	FindTasks(lookups []model.TaskLookup) ([]model.Task, error)
+
 	DeleteTask(taskId int) error
+
+	RemoveTasks(taskIds []int) error
+}
+
+type ArchiveRepository interface {
+	ArchiveTasks(tasks []model.Task) error
+
+	GetArchivedTasks(from time.Time, to time.Time) ([]model.Task, error)
+}
+
+type CommentRepository interface {
+	AddComment(comment model.Comment) (model.Comment, error)
+
+	GetAllComments() ([]model.Comment, error)
+
+	GetComments(taskId int) ([]model.Comment, error)
+
+	GetComment(commentId int) (model.Comment, error)
+
+	CountComments() (map[int]int, error)
+
+	UpdateComment(comment model.Comment) error
+
+	DeleteComment(commentId int) error
+
+	DeleteTaskComments(taskId int) error
+}
+
+type AttachmentRepository interface {
+	AddAttachment(attachment model.Attachment) (model.Attachment, error)
+
+	GetAllAttachments() ([]model.Attachment, error)
+
+	GetAttachments(taskId int) ([]model.Attachment, error)
+
+	GetAttachment(attachmentId int) (model.Attachment, error)
+
+	DeleteAttachment(attachmentId int) error
+
+	DeleteTaskAttachments(taskId int) error
 }
 
+// BlobStore keeps the content of attachments addressed by its SHA-256 hash.
+type BlobStore interface {
+	Put(reader io.Reader) (hash string, size int64, err error)
+
+	Open(hash string) (*os.File, error)
+
+	Delete(hash string) error
+
+	List() (map[string]time.Time, error)
+}
+
+type WorkLogRepository interface {
+	AddWorkLog(workLog model.WorkLog) (model.WorkLog, error)
+
+	GetAllWorkLogs() ([]model.WorkLog, error)
+
+	UpdateWorkLog(workLog model.WorkLog) error
+
+	DeleteTaskWorkLogs(taskId int) error
+}
+
+type ProjectRepository interface {
+	AddProject(project model.Project) (model.Project, error)
+
+	GetAllProjects() ([]model.Project, error)
+
+	GetProject(projectId int) (model.Project, error)
+
+	GetProjectByKey(key string) (model.Project, error)
+
+	UpdateProject(project model.Project) error
+
+	DeleteProject(projectId int) error
+
+	NextTaskNumber(projectId int) (int, error)
+}
+
+type SprintRepository interface {
+	AddSprint(sprint model.Sprint) (model.Sprint, error)
+
+	GetAllSprints() ([]model.Sprint, error)
+
+	GetSprint(sprintId int) (model.Sprint, error)
+
+	UpdateSprint(sprint model.Sprint) error
+}
+
+type HistoryRepository interface {
+	AddRevision(revision model.TaskRevision) error
+
+	GetAllRevisions() ([]model.TaskRevision, error)
+
+	GetRevisions(taskId int) ([]model.TaskRevision, error)
+
+	GetRevisionsOf(taskIds []int) ([]model.TaskRevision, error)
+}
+
+type UserRepository interface {
+	AddUser(user model.User) (model.User, error)
+
+	GetAllUsers() ([]model.User, error)
+
+	GetUser(userId int) (model.User, error)
+}
+
+// Repositories groups the storage used by TaskService.
+type Repositories struct {
+	Tasks       TaskRepository
+	Users       UserRepository
+	Comments    CommentRepository
+	Attachments AttachmentRepository
+	Blobs       BlobStore
+	WorkLogs    WorkLogRepository
+	Projects    ProjectRepository
+	History     HistoryRepository
+	Sprints     SprintRepository
+	Archive     ArchiveRepository
+}
+
+// Config holds the limits enforced by TaskService.
+type Config struct {
+	// MaxAttachmentSize is the maximum size in bytes of a single attachment.
+	MaxAttachmentSize int64
+	// MaxTaskAttachmentsSize is the maximum size in bytes of all attachments of a task.
+	MaxTaskAttachmentsSize int64
+	// ArchiveAfter is how long tasks stay Done before being archived, zero disables archiving.
+	ArchiveAfter time.Duration
+}
+
+// Error is a failure along with a message that can be shown to the client. Its kind is
+// one of the model sentinel errors, or nil when the wrapped error tells the kind.
 type Error struct {
 	UserMsg string
+	kind    error
 	err     error
 }
 
@@ -26,6 +170,15 @@ func (e Error) Error() string {
 	return e.err.Error()
 }
 
+func (e Error) Unwrap() error {
+	return e.err
+}
+
+// Is reports whether target is the kind of e, so errors.Is finds it.
+func (e Error) Is(target error) bool {
+	return e.kind != nil && target == e.kind
+}
+
 func NewError(err error, userMsg string) Error {
 	return Error{
 		UserMsg: userMsg,
@@ -33,70 +186,611 @@ func NewError(err error, userMsg string) Error {
 	}
 }
 
+// invalidError is an Error about input that can't be used, such as a reference to a user
+// that doesn't exist.
+func invalidError(err error, userMsg string) Error {
+	return Error{UserMsg: userMsg, kind: model.ErrValidation, err: err}
+}
+
+// conflictError is an Error about a change the current state doesn't allow.
+func conflictError(err error, userMsg string) Error {
+	return Error{UserMsg: userMsg, kind: model.ErrConflict, err: err}
+}
+
+func forbiddenError(err error, userMsg string) Error {
+	return Error{UserMsg: userMsg, kind: model.ErrForbidden, err: err}
+}
+
+func notFoundError(err error, userMsg string) Error {
+	return Error{UserMsg: userMsg, kind: model.ErrNotFound, err: err}
+}
+
 type TaskService struct {
-	repository TaskRepository
-	log        slog.Logger
+	repository  TaskRepository
+	users       UserRepository
+	comments    CommentRepository
+	attachments AttachmentRepository
+	blobs       BlobStore
+	workLogs    WorkLogRepository
+	projects    ProjectRepository
+	history     HistoryRepository
+	sprints     SprintRepository
+	archive     ArchiveRepository
+	config      Config
+	// searchIndex indexes the descriptions and comments of the tasks for Search.
+	searchIndex *search.Index
+	// events publishes the changes made to the tasks to the subscribers of SubscribeEvents.
+	events *eventBroker
+	// taskMutex serializes updates that read a task before changing it.
+	taskMutex *sync.Mutex
+	// attachmentMutex serializes the checks of the attachment size limits with the
+	// storage of the attachment metadata.
+	attachmentMutex *sync.Mutex
+	// workLogMutex keeps users from running more than one timer.
+	workLogMutex *sync.Mutex
+	log          slog.Logger
 }
 
-func NewTaskService(repository TaskRepository, log *slog.Logger) TaskService {
-	return TaskService{repository: repository, log: *log}
+func NewTaskService(repositories Repositories, config Config, log *slog.Logger) TaskService {
+	return TaskService{
+		repository:      repositories.Tasks,
+		users:           repositories.Users,
+		comments:        repositories.Comments,
+		attachments:     repositories.Attachments,
+		blobs:           repositories.Blobs,
+		workLogs:        repositories.WorkLogs,
+		projects:        repositories.Projects,
+		history:         repositories.History,
+		sprints:         repositories.Sprints,
+		archive:         repositories.Archive,
+		config:          config,
+		searchIndex:     search.NewIndex(),
+		events:          newEventBroker(eventReplaySize),
+		taskMutex:       &sync.Mutex{},
+		attachmentMutex: &sync.Mutex{},
+		workLogMutex:    &sync.Mutex{},
+		log:             *log,
+	}
 }
 
-func (s *TaskService) AddTask(newTask model.CreateTask) error {
+// AddTask stores newTask and returns it with its id, reporterId is the id of the user
+// creating it or 0 when anonymous.
+func (s *TaskService) AddTask(newTask model.CreateTask, reporterId int) (model.Task, error) {
+	if err := validateCreateTask(newTask); err != nil {
+		s.log.Info(fmt.Sprintf("invalid task: %s", err))
+		return model.Task{}, err
+	}
+
+	if err := s.checkAssignee(newTask.AssigneeId); err != nil {
+		return model.Task{}, err
+	}
+
+	if err := s.checkRecurrence(newTask.Recurrence); err != nil {
+		return model.Task{}, err
+	}
+
+	if err := s.checkSprint(newTask.SprintId); err != nil {
+		return model.Task{}, err
+	}
+
+	remaining := newTask.OriginalEstimate
+	if newTask.RemainingEstimate != nil {
+		remaining = *newTask.RemainingEstimate
+	}
+
+	if err := s.checkEstimates(newTask.StoryPoints, newTask.OriginalEstimate, remaining); err != nil {
+		return model.Task{}, err
+	}
+
+	var project model.Project
+	if newTask.Project != "" {
+		var err error
+		if project, err = s.projects.GetProjectByKey(newTask.Project); err != nil {
+			err = fmt.Errorf("failed to find project: %w", err)
+			s.log.Error(err.Error())
+			return model.Task{}, invalidError(err, "project does not exist")
+		}
+
+		if newTask.AssigneeId == 0 {
+			newTask.AssigneeId = project.DefaultAssigneeId
+		}
+
+		if err := s.checkRequiredFields(project, newTask.CustomFields); err != nil {
+			return model.Task{}, err
+		}
+	}
+
+	customFields, err := s.checkCustomFields(project, newTask.CustomFields)
+	if err != nil {
+		return model.Task{}, err
+	}
 
 	task := model.Task{
+		ProjectId:   project.Id,
+		SprintId:    newTask.SprintId,
 		Description: newTask.Description,
 		Status:      newTask.Status,
-		CreatedAt:   model.DateTime(time.Now()),
-		UpdatedAt:   model.DateTime(time.Now()),
+		AssigneeId:  newTask.AssigneeId,
+		ReporterId:  reporterId,
+		DueAt:       newTask.DueAt,
+		Recurrence:  newTask.Recurrence,
+		Tags:        normalizeTags(newTask.Tags),
+		StoryPoints: newTask.StoryPoints,
+
+		OriginalEstimate:  newTask.OriginalEstimate,
+		RemainingEstimate: remaining,
+
+		Checklist:             newChecklist(newTask.Checklist),
+		CompleteWithChecklist: newTask.CompleteWithChecklist,
+		CustomFields:          customFields,
+		CreatedAt:             model.Now(),
+		UpdatedAt:             model.Now(),
+	}
+
+	created, err := s.saveTask(task, reporterId)
+	if err != nil {
+		return model.Task{}, NewError(err, "error when creating task")
+	}
+
+	if created.Recurrence != nil && created.Status == model.Done {
+		return s.addNextOccurrence(created)
+	}
+	return created, nil
+}
+
+// saveTask gives task a key when it belongs to a project, stores it and records its creation.
+func (s *TaskService) saveTask(task model.Task, actorId int) (model.Task, error) {
+	if task.Status == model.InProgress && task.StartedAt == nil {
+		startedAt := model.Now()
+		task.StartedAt = &startedAt
 	}
 
-	err := s.repository.AddTask(task)
+	if task.Status == model.Done && task.CompletedAt == nil {
+		completedAt := model.Now()
+		task.CompletedAt = &completedAt
+	}
+
+	if task.ProjectId != 0 {
+		key, err := s.nextTaskKey(task.ProjectId)
+		if err != nil {
+			s.log.Error(err.Error())
+			return model.Task{}, err
+		}
+		task.Key = key
+	}
+
+	task, err := s.repository.AddTask(task)
 	if err != nil {
 		err = fmt.Errorf("failed to create task: %w", err)
 		s.log.Error(err.Error())
-		return NewError(err, "error when creating user")
+		return model.Task{}, err
 	}
-	return nil
+
+	s.recordRevision(model.TaskCreated, model.Task{}, task, actorId)
+	s.indexTask(task)
+	return task, nil
 }
 
-func (s *TaskService) GetTasks(status model.TaskStatus, description string) ([]model.Task, error) {
+// saveUpdate applies update to task through the repository, records the change and
+// returns the task as updated.
+func (s *TaskService) saveUpdate(task model.Task, update model.UpdateTask, revisionType model.RevisionType, actorId int) (model.Task, error) {
+	if update.Status != nil && (*update.Status == model.Done) != (task.Status == model.Done) {
+		// a zero time clears the completion of reopened tasks
+		completedAt := model.DateTime{}
+		if *update.Status == model.Done {
+			completedAt = model.Now()
+		}
+		update.CompletedAt = &completedAt
+	}
+
+	if update.Status != nil && *update.Status == model.InProgress && task.StartedAt == nil {
+		startedAt := model.Now()
+		update.StartedAt = &startedAt
+	}
 
-	tasks, err := s.repository.GetAllTasks()
+	updated, err := s.repository.UpdateTask(task.Id, update)
 	if err != nil {
-		s.log.Error(fmt.Sprintf("failed to get tasks using filters status=%d, description=%s", status, description), slog.Any("err", err))
-		return nil, fmt.Errorf("failed to get tasks: %w", err)
+		s.log.Error(fmt.Sprintf("error when updating task: %s", err))
+		return model.Task{}, fmt.Errorf("failed to update task: %w", err)
 	}
 
-	if status == -1 && description == "" {
-		return tasks, nil
+	s.recordRevision(revisionType, task, updated, actorId)
+	if updated.Description != task.Description {
+		s.indexTask(updated)
 	}
+	return updated, nil
+}
 
-	tasksFiltered := make([]model.Task, 0)
+// GetTask returns the task taskId.
+func (s *TaskService) GetTask(taskId int) (model.Task, error) {
+	task, err := s.findTask(taskId)
+	if err != nil {
+		s.log.Info(fmt.Sprintf("failed to get task %d: %s", taskId, err))
+		return model.Task{}, err
+	}
+	return task, nil
+}
+
+// GetTasks returns the page of the tasks matching filter, ordered by sort and then by id.
+func (s *TaskService) GetTasks(filter model.TaskFilter, sort model.TaskSort, page model.TaskPage) (model.TaskListPage, error) {
+	if err := validatePage(page, sort); err != nil {
+		s.log.Info(fmt.Sprintf("invalid page: %s", err))
+		return model.TaskListPage{}, err
+	}
+
+	matcher, err := s.newTaskMatcher(filter)
+	if err != nil {
+		return model.TaskListPage{}, err
+	}
+
+	tasks, err := s.findTasks(matcher)
+	if err != nil {
+		return model.TaskListPage{}, err
+	}
+
+	commentCounts, err := s.comments.CountComments()
+	if err != nil {
+		s.log.Error("failed to count comments", slog.Any("err", err))
+		return model.TaskListPage{}, fmt.Errorf("failed to get tasks: %w", err)
+	}
+
+	tasksFiltered := make([]model.TaskListItem, 0, len(tasks))
 	for _, task := range tasks {
-		if (description == "" || description == task.Description) && (status == -1 || task.Status == status) {
-			tasksFiltered = append(tasksFiltered, task)
+		tasksFiltered = append(tasksFiltered, model.TaskListItem{
+			Task:              task,
+			CommentCount:      commentCounts[task.Id],
+			ChecklistProgress: model.Progress(task.Checklist),
+		})
+	}
+	result, err := paginate(tasksFiltered, sort, page)
+	if err != nil {
+		s.log.Error("failed to page tasks", slog.Any("err", err))
+		return model.TaskListPage{}, fmt.Errorf("failed to get tasks: %w", err)
+	}
+	return result, nil
+}
+
+// GetUserTasks returns the page of the tasks assigned to the user userId, ordered by sort.
+func (s *TaskService) GetUserTasks(userId int, sort model.TaskSort, page model.TaskPage) (model.TaskListPage, error) {
+	if _, err := s.users.GetUser(userId); err != nil {
+		if errors.Is(err, model.ErrNotFound) {
+			s.log.Info(fmt.Sprintf("user %d does not exist", userId))
+			return model.TaskListPage{}, notFoundError(err, "user does not exist")
 		}
+		s.log.Error(fmt.Sprintf("failed to get user %d", userId), slog.Any("err", err))
+		return model.TaskListPage{}, fmt.Errorf("failed to get user tasks: %w", err)
+	}
+
+	return s.GetTasks(model.TaskFilter{AssigneeId: userId}, sort, page)
+}
+
+func matchesFilter(task model.Task, filter model.TaskFilter) bool {
+	if filter.Description != "" && filter.Description != task.Description {
+		return false
+	}
+
+	if filter.Text != "" && !strings.Contains(strings.ToLower(task.Description), strings.ToLower(filter.Text)) {
+		return false
+	}
+
+	if filter.Status != nil && task.Status != *filter.Status {
+		return false
+	}
+
+	if filter.AssigneeId != 0 && task.AssigneeId != filter.AssigneeId {
+		return false
+	}
+
+	if filter.Tag != "" && !slices.Contains(task.Tags, filter.Tag) {
+		return false
+	}
+
+	if filter.ProjectId != 0 && task.ProjectId != filter.ProjectId {
+		return false
+	}
+
+	if filter.SprintId != 0 && task.SprintId != filter.SprintId {
+		return false
 	}
 
-	return tasksFiltered, nil
+	return matchesCustomFields(task, filter.CustomFields)
 }
 
-func (s *TaskService) UpdateTask(taskId int, taskToUpdate model.UpdateTask) error {
+// UpdateTask applies taskToUpdate to the task taskId, actorId is the id of the user making
+// the change or 0 when anonymous.
+func (s *TaskService) UpdateTask(taskId int, taskToUpdate model.UpdateTask, actorId int) (model.Task, error) {
 	s.log.Info(fmt.Sprintf("Updating task %d with values %+v", taskId, taskToUpdate))
-	if err := s.repository.UpdateTask(taskId, taskToUpdate); err != nil {
+	if err := s.checkUpdate(taskId, &taskToUpdate); err != nil {
+		return model.Task{}, err
+	}
+
+	s.taskMutex.Lock()
+	defer s.taskMutex.Unlock()
+
+	task, err := s.findTask(taskId)
+	if err != nil {
 		s.log.Error(fmt.Sprintf("error when updating task: %s", err))
-		return fmt.Errorf("failed to update task: %w", err)
+		return model.Task{}, fmt.Errorf("failed to update task: %w", err)
+	}
+	return s.applyUpdate(task, taskToUpdate, actorId)
+}
+
+// checkUpdate validates taskToUpdate and the users, sprint and recurrence it refers to,
+// and normalizes its tags.
+func (s *TaskService) checkUpdate(taskId int, taskToUpdate *model.UpdateTask) error {
+	if err := validateUpdateTask(*taskToUpdate); err != nil {
+		s.log.Info(fmt.Sprintf("invalid update of task %d: %s", taskId, err))
+		return err
+	}
+
+	if taskToUpdate.AssigneeId != nil {
+		if err := s.checkAssignee(*taskToUpdate.AssigneeId); err != nil {
+			return err
+		}
+	}
+
+	if taskToUpdate.Recurrence != nil && taskToUpdate.Recurrence.Frequency != "" {
+		if err := s.checkRecurrence(taskToUpdate.Recurrence); err != nil {
+			return err
+		}
+	}
+
+	if taskToUpdate.SprintId != nil {
+		if err := s.checkSprint(*taskToUpdate.SprintId); err != nil {
+			return err
+		}
+	}
+
+	if taskToUpdate.Tags != nil {
+		tags := normalizeTags(*taskToUpdate.Tags)
+		taskToUpdate.Tags = &tags
 	}
-	s.log.Info(fmt.Sprintf("Task %d updated with values %+v", taskId, taskToUpdate))
 	return nil
 }
 
-func (s *TaskService) DeleteTask(taskId int) error {
+// applyUpdate checks taskToUpdate against task and the rules of its project, saves it and
+// creates the next occurrence of a recurring task it completes. The caller holds taskMutex.
+func (s *TaskService) applyUpdate(task model.Task, taskToUpdate model.UpdateTask, actorId int) (model.Task, error) {
+	taskId := task.Id
+	previousStatus := task.Status
+
+	if taskToUpdate.CustomFields != nil {
+		project, err := s.taskProject(task)
+		if err != nil {
+			return model.Task{}, err
+		}
+
+		if taskToUpdate.CustomFields, err = s.checkCustomFields(project, taskToUpdate.CustomFields); err != nil {
+			return model.Task{}, err
+		}
+	}
+
+	updated := task
+	taskToUpdate.Apply(&updated)
+	if err := s.checkEstimates(updated.StoryPoints, updated.OriginalEstimate, updated.RemainingEstimate); err != nil {
+		return model.Task{}, err
+	}
+
+	if err := s.checkWorkflow(task, updated.Status); err != nil {
+		return model.Task{}, err
+	}
+
+	updated, err := s.saveUpdate(task, taskToUpdate, model.TaskUpdated, actorId)
+	if err != nil {
+		return model.Task{}, err
+	}
+	s.log.Info(fmt.Sprintf("Task %d updated with values %+v", taskId, taskToUpdate))
+
+	if updated.Recurrence != nil && previousStatus != model.Done && updated.Status == model.Done {
+		if updated, err = s.addNextOccurrence(updated); err != nil {
+			return model.Task{}, err
+		}
+	}
+	return updated, nil
+}
+
+// ReplaceTask replaces every editable field of the task taskId with the values of
+// replacement, see model.ReplaceTask, and returns the task as stored. actorId is the id of
+// the user making the change or 0 when anonymous.
+func (s *TaskService) ReplaceTask(taskId int, replacement model.ReplaceTask, actorId int) (model.Task, error) {
+	return s.PatchTask(taskId, func(model.Task) (model.ReplaceTask, error) { return replacement, nil }, actorId)
+}
+
+// PatchTask replaces every editable field of the task taskId with the values patch builds
+// from the task as stored, and returns the task as stored. The task is locked from the
+// read to the write, so no other change is lost in between.
+func (s *TaskService) PatchTask(taskId int, patch func(task model.Task) (model.ReplaceTask, error), actorId int) (model.Task, error) {
+	s.taskMutex.Lock()
+	defer s.taskMutex.Unlock()
+
+	task, err := s.findTask(taskId)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("error when replacing task: %s", err))
+		return model.Task{}, fmt.Errorf("failed to replace task: %w", err)
+	}
+
+	replacement, err := patch(task)
+	if err != nil {
+		return model.Task{}, err
+	}
+
+	taskToUpdate := replacement.Update(task)
+	s.log.Info(fmt.Sprintf("Updating task %d with values %+v", taskId, taskToUpdate))
+	if err := s.checkUpdate(taskId, &taskToUpdate); err != nil {
+		return model.Task{}, err
+	}
+	return s.applyUpdate(task, taskToUpdate, actorId)
+}
+
+// addNextOccurrence creates the task following the recurring task done. The recurrence
+// moves to the new task so reopening and completing done again doesn't repeat it, done
+// is returned without it.
+func (s *TaskService) addNextOccurrence(done model.Task) (model.Task, error) {
+	// recurrences follow the calendar of the server, so weekdays and month days are local
+	now := time.Now()
+	due := now
+	if done.DueAt != nil {
+		due = time.Time(*done.DueAt).Local()
+	}
+	recurrence := done.Recurrence.Anchor(due)
+	nextDue := model.DateTime(recurrence.Next(due, now).UTC())
+
+	next := model.Task{
+		ProjectId:   done.ProjectId,
+		Description: done.Description,
+		Status:      model.TODO,
+		AssigneeId:  done.AssigneeId,
+		ReporterId:  done.ReporterId,
+		DueAt:       &nextDue,
+		Recurrence:  &recurrence,
+		Tags:        done.Tags,
+		StoryPoints: done.StoryPoints,
+
+		OriginalEstimate:  done.OriginalEstimate,
+		RemainingEstimate: done.OriginalEstimate,
+
+		Checklist:             uncheckedChecklist(done.Checklist),
+		CompleteWithChecklist: done.CompleteWithChecklist,
+		CreatedAt:             model.Now(),
+		UpdatedAt:             model.Now(),
+	}
+
+	// the recurrence is stopped first so a retry can't create the occurrence twice
+	stopped, err := s.saveUpdate(done, model.UpdateTask{Recurrence: &model.Recurrence{}}, model.TaskUpdated, 0)
+	if err != nil {
+		return model.Task{}, fmt.Errorf("failed to stop recurrence of task %d: %w", done.Id, err)
+	}
+
+	if _, err := s.saveTask(next, 0); err != nil {
+		// the recurrence is given back, so completing the task again creates the occurrence
+		if _, restoreErr := s.saveUpdate(stopped, model.UpdateTask{Recurrence: done.Recurrence}, model.TaskUpdated, 0); restoreErr != nil {
+			s.log.Error(fmt.Sprintf("failed to restore recurrence of task %d: %s", done.Id, restoreErr))
+		}
+		err = fmt.Errorf("failed to create next occurrence of task %d: %w", done.Id, err)
+		return model.Task{}, NewError(err, "error when creating next occurrence")
+	}
+
+	s.log.Info(fmt.Sprintf("Created next occurrence of task %d due at %s", done.Id, nextDue.String()))
+	return stopped, nil
+}
+
+func (s *TaskService) DeleteTask(taskId int, actorId int) error {
 	s.log.Info(fmt.Sprintf("Deleting task %d...", taskId))
+	s.taskMutex.Lock()
+	defer s.taskMutex.Unlock()
+
+	task, err := s.findTask(taskId)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to delete task %d: %s", taskId, err))
+		return fmt.Errorf("failed to delete task: %w", err)
+	}
+
+	if err := s.removeLinksTo(task, actorId); err != nil {
+		return err
+	}
+
 	if err := s.repository.DeleteTask(taskId); err != nil {
 		s.log.Error(fmt.Sprintf("failed to delete task %d: %s", taskId, err))
 		return fmt.Errorf("failed to delete task: %w", err)
 	}
+	s.recordRevision(model.TaskDeleted, task, task, actorId)
+	s.searchIndex.Delete(taskId)
+
+	if err := s.comments.DeleteTaskComments(taskId); err != nil {
+		s.log.Error(fmt.Sprintf("failed to delete comments of task %d: %s", taskId, err))
+		return fmt.Errorf("failed to delete task comments: %w", err)
+	}
+
+	if err := s.workLogs.DeleteTaskWorkLogs(taskId); err != nil {
+		s.log.Error(fmt.Sprintf("failed to delete work logs of task %d: %s", taskId, err))
+		return fmt.Errorf("failed to delete task work logs: %w", err)
+	}
+
+	if err := s.attachments.DeleteTaskAttachments(taskId); err != nil {
+		s.log.Error(fmt.Sprintf("failed to delete attachments of task %d: %s", taskId, err))
+		return fmt.Errorf("failed to delete task attachments: %w", err)
+	}
+
+	if err := s.CollectGarbage(); err != nil {
+		s.log.Error(fmt.Sprintf("failed to collect blobs of task %d: %s", taskId, err))
+	}
+	return nil
+}
+
+// findTask returns the task with id taskId.
+func (s *TaskService) findTask(taskId int) (model.Task, error) {
+	task, err := s.repository.GetTask(taskId)
+	if errors.Is(err, model.ErrNotFound) {
+		return model.Task{}, notFoundError(err, "task does not exist")
+	}
+	if err != nil {
+		return model.Task{}, fmt.Errorf("failed to get task: %w", err)
+	}
+	return task, nil
+}
+
+// checkAssignee fails when assigneeId does not belong to a registered user, 0 means unassigned.
+func (s *TaskService) checkAssignee(assigneeId int) error {
+	if assigneeId == 0 {
+		return nil
+	}
+
+	if _, err := s.users.GetUser(assigneeId); err != nil {
+		err = fmt.Errorf("failed to find assignee: %w", err)
+		s.log.Error(err.Error())
+		return invalidError(err, "assignee does not exist")
+	}
+	return nil
+}
+
+// checkSprint fails when sprintId does not belong to a sprint, 0 means no sprint.
+func (s *TaskService) checkSprint(sprintId int) error {
+	if sprintId == 0 {
+		return nil
+	}
+
+	if _, err := s.sprints.GetSprint(sprintId); err != nil {
+		err = fmt.Errorf("failed to find sprint: %w", err)
+		s.log.Error(err.Error())
+		return invalidError(err, "sprint does not exist")
+	}
 	return nil
 }
+
+func (s *TaskService) checkRecurrence(recurrence *model.Recurrence) error {
+	if recurrence == nil {
+		return nil
+	}
+
+	if err := recurrence.Validate(); err != nil {
+		s.log.Info(fmt.Sprintf("invalid recurrence: %s", err))
+		return invalidError(err, err.Error())
+	}
+	return nil
+}
+
+func (s *TaskService) checkEstimates(storyPoints int, original model.Duration, remaining model.Duration) error {
+	if storyPoints < 0 || original < 0 || remaining < 0 {
+		err := fmt.Errorf("invalid estimates: story points %d, original %s, remaining %s",
+			storyPoints, time.Duration(original), time.Duration(remaining))
+		s.log.Info(err.Error())
+		return invalidError(err, "estimates can't be negative")
+	}
+	return nil
+}
+
+// normalizeTags trims tags and drops the empty and repeated ones.
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !slices.Contains(normalized, tag) {
+			normalized = append(normalized, tag)
+		}
+	}
+
+	if len(normalized) == 0 {
+		return nil
+	}
+	return normalized
+}
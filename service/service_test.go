@@ -0,0 +1,758 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-task-tracker/model"
+	"go-task-tracker/repository"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestAddTask_DoneRecurring(t *testing.T) {
+	const dirName = "Test_AddTask_DoneRecurring"
+	defer removeTestDir(dirName)
+
+	s := newTestService(t, dirName, Config{})
+
+	due := model.DateTime(time.Date(2026, 1, 7, 9, 0, 0, 0, time.Local).UTC())
+	done, err := s.AddTask(model.CreateTask{
+		Description: "Water the plants",
+		Status:      model.Done,
+		DueAt:       &due,
+		Recurrence:  &model.Recurrence{Frequency: model.Daily},
+	}, 0)
+	if err != nil {
+		t.Fatalf("expected AddTask call to return no errors, got \"%s\"", err)
+	}
+
+	if done.Recurrence != nil {
+		t.Errorf("expected the recurrence to move to the next occurrence, but task %d kept it", done.Id)
+	}
+
+	tasks, err := s.repository.GetAllTasks()
+	if err != nil {
+		t.Fatalf("failed to get tasks: %s", err)
+	}
+
+	if len(tasks) != 2 {
+		t.Fatalf("expected the task and its next occurrence, got %d tasks", len(tasks))
+	}
+
+	next := tasks[1]
+	expectedDue := time.Date(2026, 1, 8, 9, 0, 0, 0, time.Local)
+	if next.Status != model.TODO || next.Recurrence == nil || next.DueAt == nil || !time.Time(*next.DueAt).Equal(expectedDue) {
+		t.Errorf("expected a todo occurrence due at %s with the recurrence, got %+v", expectedDue, next)
+	}
+}
+
+func TestStartTimer(t *testing.T) {
+
+	var testTable = []struct {
+		autoProgress   bool
+		workflow       []model.Transition
+		expectedStatus model.TaskStatus
+		expectedError  bool
+	}{
+		{false, nil, model.TODO, false},
+		{true, nil, model.InProgress, false},
+		// the workflow rejects the move, so no timer is left running
+		{true, []model.Transition{{From: model.TODO, To: model.Done}}, model.TODO, true},
+	}
+
+	for i, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%t, %v), Expect: %s %t", testData.autoProgress, testData.workflow, testData.expectedStatus, testData.expectedError)
+
+		t.Run(testName, func(t *testing.T) {
+			dirName := fmt.Sprintf("Test_StartTimer_%d", i)
+			defer removeTestDir(dirName)
+
+			s := newTestService(t, dirName, Config{})
+			if _, err := s.projects.AddProject(model.Project{Key: "OPS", Workflow: testData.workflow}); err != nil {
+				t.Fatalf("failed to add project: %s", err)
+			}
+
+			task, err := s.AddTask(model.CreateTask{Description: "Deploy", Project: "OPS"}, 0)
+			if err != nil {
+				t.Fatalf("failed to add task: %s", err)
+			}
+
+			_, err = s.StartTimer(task.Id, 1, testData.autoProgress)
+			if (err != nil) != testData.expectedError {
+				t.Fatalf("with input (%t, %v) got error %v, but expected error %t", testData.autoProgress, testData.workflow, err, testData.expectedError)
+			}
+
+			if task, err = s.GetTask(task.Id); err != nil {
+				t.Fatalf("failed to get task: %s", err)
+			}
+			if task.Status != testData.expectedStatus {
+				t.Errorf("with input (%t, %v) got status %s, but expected %s", testData.autoProgress, testData.workflow, task.Status, testData.expectedStatus)
+			}
+
+			running, err := s.runningWorkLog(1)
+			if err != nil {
+				t.Fatalf("failed to get running timer: %s", err)
+			}
+			if (running != nil) == testData.expectedError {
+				t.Errorf("with input (%t, %v) got running timer %v, but expected one only without error", testData.autoProgress, testData.workflow, running)
+			}
+		})
+	}
+}
+
+func TestStopTimer(t *testing.T) {
+	const dirName = "Test_StopTimer"
+	defer removeTestDir(dirName)
+
+	s := newTestService(t, dirName, Config{})
+	task, err := s.AddTask(model.CreateTask{Description: "Deploy"}, 0)
+	if err != nil {
+		t.Fatalf("failed to add task: %s", err)
+	}
+
+	if _, err := s.StopTimer(task.Id, 1); !errors.Is(err, model.ErrConflict) {
+		t.Errorf("expected stopping a timer that isn't running to conflict, got %v", err)
+	}
+
+	if _, err := s.StartTimer(task.Id, 1, false); err != nil {
+		t.Fatalf("expected StartTimer call to return no errors, got \"%s\"", err)
+	}
+
+	if _, err := s.StartTimer(task.Id, 1, false); !errors.Is(err, model.ErrConflict) {
+		t.Errorf("expected starting a second timer to conflict, got %v", err)
+	}
+
+	workLog, err := s.StopTimer(task.Id, 1)
+	if err != nil {
+		t.Fatalf("expected StopTimer call to return no errors, got \"%s\"", err)
+	}
+	if workLog.Running {
+		t.Errorf("expected work log %d to be stopped", workLog.Id)
+	}
+}
+
+func TestGetWorkLogTotals(t *testing.T) {
+	const dirName = "Test_GetWorkLogTotals"
+	defer removeTestDir(dirName)
+
+	s := newTestService(t, dirName, Config{})
+	for range 2 {
+		if _, err := s.AddTask(model.CreateTask{Description: "Deploy"}, 0); err != nil {
+			t.Fatalf("failed to add task: %s", err)
+		}
+	}
+
+	at := func(value string) *model.DateTime {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatalf("failed to parse time: %s", err)
+		}
+		dateTime := model.DateTime(parsed)
+		return &dateTime
+	}
+
+	workLogs := []struct {
+		taskId    int
+		userId    int
+		hours     int
+		startedAt string
+	}{
+		{1, 1, 1, "2026-03-02T09:00:00Z"},
+		{1, 2, 2, "2026-03-02T23:30:00Z"},
+		{2, 1, 4, "2026-03-03T10:00:00Z"},
+	}
+	for _, workLog := range workLogs {
+		_, err := s.AddWorkLog(workLog.taskId, workLog.userId, model.CreateWorkLog{Duration: hours(workLog.hours), StartedAt: at(workLog.startedAt)})
+		if err != nil {
+			t.Fatalf("failed to log work: %s", err)
+		}
+	}
+
+	// running timers aren't counted
+	if _, err := s.StartTimer(2, 2, false); err != nil {
+		t.Fatalf("failed to start timer: %s", err)
+	}
+
+	paris, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %s", err)
+	}
+
+	var testTable = []struct {
+		filter   model.WorkLogFilter
+		groupBy  string
+		expected []model.WorkLogTotal
+	}{
+		{model.WorkLogFilter{}, "", []model.WorkLogTotal{{Duration: hours(7)}}},
+		{model.WorkLogFilter{}, GroupByTask, []model.WorkLogTotal{{TaskId: 1, Duration: hours(3)}, {TaskId: 2, Duration: hours(4)}}},
+		{model.WorkLogFilter{TaskId: 1}, GroupByUser, []model.WorkLogTotal{{UserId: 1, Duration: hours(1)}, {UserId: 2, Duration: hours(2)}}},
+		{model.WorkLogFilter{}, GroupByDate, []model.WorkLogTotal{{Date: "2026-03-02", Duration: hours(3)}, {Date: "2026-03-03", Duration: hours(4)}}},
+		// late evening in UTC is already the next day in Paris
+		{model.WorkLogFilter{Location: paris}, GroupByDate, []model.WorkLogTotal{{Date: "2026-03-02", Duration: hours(1)}, {Date: "2026-03-03", Duration: hours(6)}}},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%+v, %s), Expect: %v", testData.filter, testData.groupBy, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			answer, err := s.GetWorkLogTotals(testData.filter, testData.groupBy)
+			if err != nil {
+				t.Fatalf("with input (%+v, %s) got error \"%s\"", testData.filter, testData.groupBy, err)
+			}
+			if !slices.Equal(answer, testData.expected) {
+				t.Errorf("with input (%+v, %s) got %v, but expected %v", testData.filter, testData.groupBy, answer, testData.expected)
+			}
+		})
+	}
+
+	if _, err := s.GetWorkLogTotals(model.WorkLogFilter{}, "month"); !errors.Is(err, model.ErrValidation) {
+		t.Errorf("expected grouping by month to be invalid, got %v", err)
+	}
+}
+
+func TestGetEstimateTotals(t *testing.T) {
+	const dirName = "Test_GetEstimateTotals"
+	defer removeTestDir(dirName)
+
+	s := newTestService(t, dirName, Config{})
+	remaining := hours(1)
+	tasks := []model.CreateTask{
+		{Description: "A", Tags: []string{"ui", "api"}, StoryPoints: 3, OriginalEstimate: hours(4)},
+		{Description: "B", Tags: []string{"ui"}, StoryPoints: 5, OriginalEstimate: hours(8), RemainingEstimate: &remaining},
+		{Description: "C", Status: model.Done, StoryPoints: 1},
+	}
+	for _, task := range tasks {
+		if _, err := s.AddTask(task, 0); err != nil {
+			t.Fatalf("failed to add task: %s", err)
+		}
+	}
+
+	var testTable = []struct {
+		filter   model.TaskFilter
+		groupBy  string
+		expected []string
+	}{
+		{model.TaskFilter{}, "", []string{"/:3 9 12h0m0s 5h0m0s"}},
+		{model.TaskFilter{Tag: "ui"}, "", []string{"/:2 8 12h0m0s 5h0m0s"}},
+		{model.TaskFilter{}, GroupByStatus, []string{"todo/:2 8 12h0m0s 5h0m0s", "done/:1 1 0s 0s"}},
+		// tasks are counted once per tag, tasks without tags not at all
+		{model.TaskFilter{}, GroupByTag, []string{"/api:1 3 4h0m0s 4h0m0s", "/ui:2 8 12h0m0s 5h0m0s"}},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%+v, %s), Expect: %v", testData.filter, testData.groupBy, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			totals, err := s.GetEstimateTotals(testData.filter, testData.groupBy)
+			if err != nil {
+				t.Fatalf("with input (%+v, %s) got error \"%s\"", testData.filter, testData.groupBy, err)
+			}
+
+			var answer []string
+			for _, total := range totals {
+				var status string
+				if total.Status != nil {
+					status = total.Status.Name()
+				}
+				answer = append(answer, fmt.Sprintf("%s/%s:%d %d %s %s", status, total.Tag, total.Tasks, total.StoryPoints,
+					time.Duration(total.OriginalEstimate), time.Duration(total.RemainingEstimate)))
+			}
+			if !slices.Equal(answer, testData.expected) {
+				t.Errorf("with input (%+v, %s) got %v, but expected %v", testData.filter, testData.groupBy, answer, testData.expected)
+			}
+		})
+	}
+}
+
+func TestReduceRemainingEstimate(t *testing.T) {
+
+	var testTable = []struct {
+		original int
+		logged   []int
+		expected int
+	}{
+		{8, []int{3}, 5},
+		{8, []int{3, 5}, 0},
+		// the remaining estimate doesn't go below zero
+		{8, []int{6, 5}, 0},
+		// tasks without estimate are left without one
+		{0, []int{2}, 0},
+	}
+
+	for i, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%d, %v), Expect: %d", testData.original, testData.logged, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			dirName := fmt.Sprintf("Test_ReduceRemainingEstimate_%d", i)
+			defer removeTestDir(dirName)
+
+			s := newTestService(t, dirName, Config{})
+			task, err := s.AddTask(model.CreateTask{Description: "Deploy", OriginalEstimate: hours(testData.original)}, 0)
+			if err != nil {
+				t.Fatalf("failed to add task: %s", err)
+			}
+
+			for _, logged := range testData.logged {
+				if _, err := s.AddWorkLog(task.Id, 1, model.CreateWorkLog{Duration: hours(logged)}); err != nil {
+					t.Fatalf("failed to log work: %s", err)
+				}
+			}
+
+			if task, err = s.GetTask(task.Id); err != nil {
+				t.Fatalf("failed to get task: %s", err)
+			}
+			if task.RemainingEstimate != hours(testData.expected) || task.OriginalEstimate != hours(testData.original) {
+				t.Errorf("with input (%d, %v) got %s remaining of %s, but expected %dh remaining", testData.original, testData.logged,
+					time.Duration(task.RemainingEstimate), time.Duration(task.OriginalEstimate), testData.expected)
+			}
+
+			history, err := s.GetHistory(task.Id)
+			if err != nil {
+				t.Fatalf("failed to get history: %s", err)
+			}
+			if last := history[len(history)-1]; testData.original != 0 && last.ActorId != 1 {
+				t.Errorf("with input (%d, %v) expected the reduction to be recorded for user 1, got %+v", testData.original, testData.logged, last)
+			}
+		})
+	}
+}
+
+func TestUpdateTask_Actor(t *testing.T) {
+	const dirName = "Test_UpdateTask_Actor"
+	defer removeTestDir(dirName)
+
+	s := newTestService(t, dirName, Config{})
+	task, err := s.AddTask(model.CreateTask{Description: "Deploy"}, 3)
+	if err != nil {
+		t.Fatalf("failed to add task: %s", err)
+	}
+
+	description := "Deploy on friday"
+	if _, err := s.UpdateTask(task.Id, model.UpdateTask{Description: &description}, 7); err != nil {
+		t.Fatalf("expected UpdateTask call to return no errors, got \"%s\"", err)
+	}
+
+	history, err := s.GetHistory(task.Id)
+	if err != nil {
+		t.Fatalf("failed to get history: %s", err)
+	}
+
+	var actors []int
+	for _, revision := range history {
+		actors = append(actors, revision.ActorId)
+	}
+	if !slices.Equal(actors, []int{3, 7}) {
+		t.Errorf("got revisions by %v, but expected them by [3 7]", actors)
+	}
+}
+
+func TestPatchTask_Concurrent(t *testing.T) {
+	const dirName = "Test_PatchTask_Concurrent"
+	defer removeTestDir(dirName)
+
+	s := newTestService(t, dirName, Config{})
+	task, err := s.AddTask(model.CreateTask{Description: "Deploy"}, 0)
+	if err != nil {
+		t.Fatalf("failed to add task: %s", err)
+	}
+
+	// every patch adds one tag to the tags it reads, so a lost update loses a tag
+	const patches = 20
+	var wg sync.WaitGroup
+	for i := 0; i < patches; i++ {
+		wg.Add(1)
+		go func(tag string) {
+			defer wg.Done()
+			_, err := s.PatchTask(task.Id, func(task model.Task) (model.ReplaceTask, error) {
+				replacement := model.NewReplaceTask(task)
+				replacement.Tags = append(slices.Clone(replacement.Tags), tag)
+				return replacement, nil
+			}, 0)
+			if err != nil {
+				t.Errorf("expected PatchTask call to return no errors, got \"%s\"", err)
+			}
+		}(fmt.Sprintf("tag%d", i))
+	}
+	wg.Wait()
+
+	if task, err = s.GetTask(task.Id); err != nil {
+		t.Fatalf("failed to get task: %s", err)
+	}
+	if len(task.Tags) != patches {
+		t.Errorf("expected %d tags after %d concurrent patches, got %v", patches, patches, task.Tags)
+	}
+}
+
+func TestCheckChecklistItem(t *testing.T) {
+
+	var testTable = []struct {
+		completeWithChecklist bool
+		workflow              []model.Transition
+		expectedStatus        model.TaskStatus
+		expectedChecked       bool
+		expectedError         error
+	}{
+		{false, nil, model.TODO, true, nil},
+		{true, nil, model.Done, true, nil},
+		// the workflow rejects the move to done, so the item is left unchecked
+		{true, []model.Transition{{From: model.TODO, To: model.InProgress}}, model.TODO, false, model.ErrConflict},
+	}
+
+	for i, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%t, %v), Expect: %s %t %v", testData.completeWithChecklist, testData.workflow, testData.expectedStatus, testData.expectedChecked, testData.expectedError)
+
+		t.Run(testName, func(t *testing.T) {
+			dirName := fmt.Sprintf("Test_CheckChecklistItem_%d", i)
+			defer removeTestDir(dirName)
+
+			s := newTestService(t, dirName, Config{})
+			if _, err := s.projects.AddProject(model.Project{Key: "OPS", Workflow: testData.workflow}); err != nil {
+				t.Fatalf("failed to add project: %s", err)
+			}
+
+			task, err := s.AddTask(model.CreateTask{
+				Description:           "Release",
+				Project:               "OPS",
+				Checklist:             []string{"Tag", "Publish"},
+				CompleteWithChecklist: testData.completeWithChecklist,
+			}, 0)
+			if err != nil {
+				t.Fatalf("failed to add task: %s", err)
+			}
+
+			if _, err := s.CheckChecklistItem(task.Id, 1, true, 0); err != nil {
+				t.Fatalf("expected CheckChecklistItem call to return no errors, got \"%s\"", err)
+			}
+
+			_, err = s.CheckChecklistItem(task.Id, 2, true, 0)
+			if !errors.Is(err, testData.expectedError) {
+				t.Fatalf("with input (%t, %v) got error %v, but expected %v", testData.completeWithChecklist, testData.workflow, err, testData.expectedError)
+			}
+
+			if task, err = s.GetTask(task.Id); err != nil {
+				t.Fatalf("failed to get task: %s", err)
+			}
+			if task.Status != testData.expectedStatus || task.Checklist[1].Checked != testData.expectedChecked {
+				t.Errorf("with input (%t, %v) got status %s and checked %t, but expected %s and %t", testData.completeWithChecklist, testData.workflow,
+					task.Status, task.Checklist[1].Checked, testData.expectedStatus, testData.expectedChecked)
+			}
+		})
+	}
+}
+
+func TestUncheckChecklistItem(t *testing.T) {
+	const dirName = "Test_UncheckChecklistItem"
+	defer removeTestDir(dirName)
+
+	s := newTestService(t, dirName, Config{})
+	task, err := s.AddTask(model.CreateTask{Description: "Release", Checklist: []string{"Tag"}}, 0)
+	if err != nil {
+		t.Fatalf("failed to add task: %s", err)
+	}
+
+	if _, err := s.CheckChecklistItem(task.Id, 1, true, 0); err != nil {
+		t.Fatalf("expected CheckChecklistItem call to return no errors, got \"%s\"", err)
+	}
+
+	item, err := s.CheckChecklistItem(task.Id, 1, false, 0)
+	if err != nil {
+		t.Fatalf("expected CheckChecklistItem call to return no errors, got \"%s\"", err)
+	}
+	if item.Checked || item.CheckedAt != nil {
+		t.Errorf("expected item %d to be unchecked, got %+v", item.Id, item)
+	}
+
+	if _, err := s.CheckChecklistItem(task.Id, 2, true, 0); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("expected checking an unknown item to fail with not found, got %v", err)
+	}
+}
+
+func TestAddChecklistItem_Limit(t *testing.T) {
+	const dirName = "Test_AddChecklistItem_Limit"
+	defer removeTestDir(dirName)
+
+	s := newTestService(t, dirName, Config{})
+	task, err := s.AddTask(model.CreateTask{Description: "Release"}, 0)
+	if err != nil {
+		t.Fatalf("failed to add task: %s", err)
+	}
+
+	for i := 0; i < maxChecklistItems; i++ {
+		if _, err := s.AddChecklistItem(task.Id, model.CreateChecklistItem{Text: fmt.Sprintf("Step %d", i+1)}, 0); err != nil {
+			t.Fatalf("expected AddChecklistItem call %d to return no errors, got \"%s\"", i+1, err)
+		}
+	}
+
+	if _, err := s.AddChecklistItem(task.Id, model.CreateChecklistItem{Text: "One step too many"}, 0); !errors.Is(err, model.ErrValidation) {
+		t.Errorf("expected adding item %d to fail with a validation error, got %v", maxChecklistItems+1, err)
+	}
+}
+
+func TestMoveTask_RequiredFields(t *testing.T) {
+	const dirName = "Test_MoveTask_RequiredFields"
+	defer removeTestDir(dirName)
+
+	s := newTestService(t, dirName, Config{})
+	if _, err := s.projects.AddProject(model.Project{Key: "DEV"}); err != nil {
+		t.Fatalf("failed to add project: %s", err)
+	}
+	fields := []model.FieldDefinition{{Name: "severity", Type: model.FieldNumber, Required: true}}
+	if _, err := s.projects.AddProject(model.Project{Key: "OPS", Fields: fields}); err != nil {
+		t.Fatalf("failed to add project: %s", err)
+	}
+
+	task, err := s.AddTask(model.CreateTask{Description: "Deploy", Project: "DEV"}, 0)
+	if err != nil {
+		t.Fatalf("failed to add task: %s", err)
+	}
+
+	if _, err := s.MoveTask(task.Id, "OPS", 0); !errors.Is(err, model.ErrValidation) {
+		t.Errorf("expected moving a task without the required fields to be invalid, got %v", err)
+	}
+
+	if task, err = s.GetTask(task.Id); err != nil {
+		t.Fatalf("failed to get task: %s", err)
+	}
+	if task.Key != "DEV-1" {
+		t.Errorf("expected the task to stay DEV-1, got %s", task.Key)
+	}
+}
+
+func TestGetTasks_UnknownProject(t *testing.T) {
+	const dirName = "Test_GetTasks_UnknownProject"
+	defer removeTestDir(dirName)
+
+	s := newTestService(t, dirName, Config{})
+	if _, err := s.GetTasks(model.TaskFilter{Project: "NOPE"}, nil, model.TaskPage{}); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("expected listing the tasks of an unknown project to fail with not found, got %v", err)
+	}
+}
+
+func TestResolveAsDuplicate_Recurring(t *testing.T) {
+	const dirName = "Test_ResolveAsDuplicate_Recurring"
+	defer removeTestDir(dirName)
+
+	s := newTestService(t, dirName, Config{})
+	canonical, err := s.AddTask(model.CreateTask{Description: "Water the plants"}, 0)
+	if err != nil {
+		t.Fatalf("failed to add task: %s", err)
+	}
+	task, err := s.AddTask(model.CreateTask{Description: "Water plants", Recurrence: &model.Recurrence{Frequency: model.Daily}}, 0)
+	if err != nil {
+		t.Fatalf("failed to add task: %s", err)
+	}
+
+	resolved, err := s.ResolveAsDuplicate(task.Id, canonical.Id, 0)
+	if err != nil {
+		t.Fatalf("expected ResolveAsDuplicate call to return no errors, got \"%s\"", err)
+	}
+	if resolved.Status != model.Done || resolved.DuplicateOf != canonical.Id || resolved.Recurrence != nil {
+		t.Errorf("expected task %d to be done as a duplicate of task %d without its recurrence, got %+v", task.Id, canonical.Id, resolved)
+	}
+
+	tasks, err := s.repository.GetAllTasks()
+	if err != nil {
+		t.Fatalf("failed to get tasks: %s", err)
+	}
+	if len(tasks) != 3 || tasks[2].Recurrence == nil {
+		t.Errorf("expected the recurrence to move to a next occurrence, got %+v", tasks)
+	}
+}
+
+func TestDeleteTask_RemovesLinks(t *testing.T) {
+	const dirName = "Test_DeleteTask_RemovesLinks"
+	defer removeTestDir(dirName)
+
+	s := newTestService(t, dirName, Config{})
+	var ids []int
+	for _, description := range []string{"Deploy", "Deploy again", "Announce"} {
+		task, err := s.AddTask(model.CreateTask{Description: description}, 0)
+		if err != nil {
+			t.Fatalf("failed to add task: %s", err)
+		}
+		ids = append(ids, task.Id)
+	}
+
+	if _, err := s.ResolveAsDuplicate(ids[1], ids[0], 0); err != nil {
+		t.Fatalf("failed to resolve duplicate: %s", err)
+	}
+	if _, err := s.LinkTasks(ids[0], model.LinkTask{Type: model.Blocks, TaskId: ids[2]}, 0); err != nil {
+		t.Fatalf("failed to link tasks: %s", err)
+	}
+
+	if err := s.DeleteTask(ids[0], 5); err != nil {
+		t.Fatalf("expected DeleteTask call to return no errors, got \"%s\"", err)
+	}
+
+	for _, id := range ids[1:] {
+		task, err := s.GetTask(id)
+		if err != nil {
+			t.Fatalf("failed to get task: %s", err)
+		}
+		if len(task.Links) != 0 || task.DuplicateOf != 0 {
+			t.Errorf("expected task %d to lose its links to task %d, got %+v and DuplicateOf %d", id, ids[0], task.Links, task.DuplicateOf)
+		}
+
+		history, err := s.GetHistory(id)
+		if err != nil {
+			t.Fatalf("failed to get history: %s", err)
+		}
+		last := history[len(history)-1]
+		if last.ActorId != 5 || !slices.Contains(last.Fields, "Links") {
+			t.Errorf("expected the unlinking of task %d to be recorded for user 5, got %+v", id, last)
+		}
+	}
+}
+
+func TestUpdateComment_OtherTask(t *testing.T) {
+	const dirName = "Test_UpdateComment_OtherTask"
+	defer removeTestDir(dirName)
+
+	s := newTestService(t, dirName, Config{})
+	var ids []int
+	for _, description := range []string{"Deploy", "Announce"} {
+		task, err := s.AddTask(model.CreateTask{Description: description}, 0)
+		if err != nil {
+			t.Fatalf("failed to add task: %s", err)
+		}
+		ids = append(ids, task.Id)
+	}
+
+	comment, err := s.AddComment(ids[0], 1, model.CreateComment{Body: "Ready"})
+	if err != nil {
+		t.Fatalf("failed to add comment: %s", err)
+	}
+
+	if _, err := s.UpdateComment(ids[1], comment.Id, 1, model.UpdateComment{Body: "Done"}); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("expected updating a comment through another task to fail with not found, got %v", err)
+	}
+	if err := s.DeleteComment(ids[1], comment.Id, 1); !errors.Is(err, model.ErrNotFound) {
+		t.Errorf("expected deleting a comment through another task to fail with not found, got %v", err)
+	}
+}
+
+func TestAddAttachment_RejectedUploadKeepsSharedBlob(t *testing.T) {
+	const dirName = "Test_AddAttachment_RejectedUploadKeepsSharedBlob"
+	defer removeTestDir(dirName)
+
+	s := newTestService(t, dirName, Config{MaxAttachmentSize: 100, MaxTaskAttachmentsSize: 10})
+	full, err := s.AddTask(model.CreateTask{Description: "Deploy"}, 0)
+	if err != nil {
+		t.Fatalf("failed to add task: %s", err)
+	}
+	if _, err := s.AddAttachment(full.Id, 0, "notes.txt", "text/plain", strings.NewReader("12345678")); err != nil {
+		t.Fatalf("failed to add attachment: %s", err)
+	}
+
+	// the same content is rejected on the full task while it is attached to the other one
+	var attachments []model.Attachment
+	for i := 0; i < 20; i++ {
+		content := fmt.Sprintf("log %02d", i)
+		empty, err := s.AddTask(model.CreateTask{Description: "Announce"}, 0)
+		if err != nil {
+			t.Fatalf("failed to add task: %s", err)
+		}
+
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := s.AddAttachment(full.Id, 0, "log.txt", "text/plain", strings.NewReader(content)); !errors.Is(err, ErrAttachmentTooLarge) {
+				t.Errorf("expected the upload to exceed the attachments of task %d, got %v", full.Id, err)
+			}
+		}()
+
+		attachment, err := s.AddAttachment(empty.Id, 0, "log.txt", "text/plain", strings.NewReader(content))
+		wg.Wait()
+		if err != nil {
+			t.Fatalf("expected AddAttachment call to return no errors, got \"%s\"", err)
+		}
+		attachments = append(attachments, attachment)
+	}
+
+	for _, attachment := range attachments {
+		_, content, err := s.OpenAttachment(attachment.TaskId, attachment.Id)
+		if err != nil {
+			t.Errorf("expected the content of attachment %d to be kept, got \"%s\"", attachment.Id, err)
+			continue
+		}
+		content.Close()
+	}
+}
+
+// newTestService returns a TaskService storing its data in the directory dirName, which
+// removeTestDir deletes.
+func newTestService(t *testing.T, dirName string, config Config) TaskService {
+	return NewTaskService(newTestRepositories(t, dirName), config, testLogger())
+}
+
+// newTestRepositories opens the stores of the services in the directory dirName.
+func newTestRepositories(t *testing.T, dirName string) Repositories {
+	if err := os.MkdirAll(dirName, 0700); err != nil {
+		t.Fatalf("failed to create test directory %s: %s", dirName, err)
+	}
+
+	path := func(name string) string { return filepath.Join(dirName, name) }
+	open := func(err error) {
+		if err != nil {
+			t.Fatalf("failed to open test store in %s: %s", dirName, err)
+		}
+	}
+
+	tasks, err := repository.NewTaskRepositoryFile(path("task_list.json"))
+	open(err)
+	users, err := repository.NewUserRepositoryFile(path("user_list.json"))
+	open(err)
+	comments, err := repository.NewCommentRepositoryFile(path("comment_list.json"))
+	open(err)
+	attachments, err := repository.NewAttachmentRepositoryFile(path("attachment_list.json"))
+	open(err)
+	blobs, err := repository.NewBlobStoreDisk(path("blobs"))
+	open(err)
+	workLogs, err := repository.NewWorkLogRepositoryFile(path("worklog_list.json"))
+	open(err)
+	projects, err := repository.NewProjectRepositoryFile(path("project_list.json"))
+	open(err)
+	history, err := repository.NewHistoryRepositoryFile(path("history_list.json"))
+	open(err)
+	sprints, err := repository.NewSprintRepositoryFile(path("sprint_list.json"))
+	open(err)
+	archive, err := repository.NewTaskArchiveDir(path("archive"))
+	open(err)
+
+	return Repositories{
+		Tasks:       &tasks,
+		Users:       users,
+		Comments:    comments,
+		Attachments: attachments,
+		Blobs:       blobs,
+		WorkLogs:    workLogs,
+		Projects:    projects,
+		History:     history,
+		Sprints:     sprints,
+		Archive:     archive,
+	}
+}
+
+func testLogger() *slog.Logger {
+	return slog.New(slog.NewTextHandler(io.Discard, nil))
+}
+
+func removeTestDir(dirName string) {
+	err := os.RemoveAll(dirName)
+	if err != nil {
+		panic(fmt.Errorf("failed to remove directory %s: %w", dirName, err))
+	}
+}
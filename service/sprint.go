@@ -0,0 +1,238 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-task-tracker/model"
+	"log/slog"
+	"math"
+	"time"
+)
+
+type SprintService struct {
+	repository SprintRepository
+	tasks      TaskRepository
+	projects   ProjectRepository
+	history    HistoryRepository
+	archive    ArchiveRepository
+	log        slog.Logger
+}
+
+func NewSprintService(repositories Repositories, log *slog.Logger) SprintService {
+	return SprintService{
+		repository: repositories.Sprints,
+		tasks:      repositories.Tasks,
+		projects:   repositories.Projects,
+		archive:    repositories.Archive,
+		history:    repositories.History,
+		log:        *log,
+	}
+}
+
+func (s *SprintService) AddSprint(newSprint model.CreateSprint) (model.Sprint, error) {
+	sprint := model.Sprint{
+		Name:      newSprint.Name,
+		Goal:      newSprint.Goal,
+		StartAt:   newSprint.StartAt,
+		EndAt:     newSprint.EndAt,
+		CreatedAt: model.Now(),
+		UpdatedAt: model.Now(),
+	}
+
+	if newSprint.Project != "" {
+		project, err := s.projects.GetProjectByKey(newSprint.Project)
+		if err != nil {
+			err = fmt.Errorf("failed to find project: %w", err)
+			s.log.Error(err.Error())
+			return model.Sprint{}, invalidError(err, "project does not exist")
+		}
+		sprint.ProjectId = project.Id
+	}
+
+	if err := s.checkDates(sprint); err != nil {
+		return model.Sprint{}, err
+	}
+
+	sprint, err := s.repository.AddSprint(sprint)
+	if err != nil {
+		err = fmt.Errorf("failed to create sprint: %w", err)
+		s.log.Error(err.Error())
+		return model.Sprint{}, NewError(err, "error when creating sprint")
+	}
+	return sprint, nil
+}
+
+func (s *SprintService) GetSprints() ([]model.Sprint, error) {
+	sprints, err := s.repository.GetAllSprints()
+	if err != nil {
+		s.log.Error("failed to get sprints", slog.Any("err", err))
+		return nil, fmt.Errorf("failed to get sprints: %w", err)
+	}
+	return sprints, nil
+}
+
+func (s *SprintService) GetSprint(sprintId int) (model.Sprint, error) {
+	sprint, err := s.repository.GetSprint(sprintId)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to get sprint %d", sprintId), slog.Any("err", err))
+		return model.Sprint{}, fmt.Errorf("failed to get sprint: %w", err)
+	}
+	return sprint, nil
+}
+
+func (s *SprintService) UpdateSprint(sprintId int, update model.UpdateSprint) (model.Sprint, error) {
+	sprint, err := s.GetSprint(sprintId)
+	if err != nil {
+		return model.Sprint{}, err
+	}
+
+	update.Apply(&sprint)
+	if err := s.checkDates(sprint); err != nil {
+		return model.Sprint{}, err
+	}
+
+	sprint.UpdatedAt = model.Now()
+	if err := s.repository.UpdateSprint(sprint); err != nil {
+		s.log.Error(fmt.Sprintf("error when updating sprint: %s", err))
+		return model.Sprint{}, fmt.Errorf("failed to update sprint: %w", err)
+	}
+	return sprint, nil
+}
+
+// GetSprintScope returns the tasks currently in the sprint along with its completion,
+// the scope it started with and how much it changed since.
+func (s *SprintService) GetSprintScope(sprintId int) (model.SprintScope, error) {
+	sprint, err := s.GetSprint(sprintId)
+	if err != nil {
+		return model.SprintScope{}, err
+	}
+
+	tasks, err := s.tasks.GetAllTasks()
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to get tasks of sprint %d", sprintId), slog.Any("err", err))
+		return model.SprintScope{}, fmt.Errorf("failed to get sprint scope: %w", err)
+	}
+
+	// tasks of the sprint archived since it started still count as done
+	archived, err := s.archive.GetArchivedTasks(time.Time(sprint.StartAt), time.Time{})
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to get archived tasks of sprint %d", sprintId), slog.Any("err", err))
+		return model.SprintScope{}, fmt.Errorf("failed to get sprint scope: %w", err)
+	}
+	tasks = append(tasks, archived...)
+
+	revisions, err := s.history.GetAllRevisions()
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to get history of sprint %d", sprintId), slog.Any("err", err))
+		return model.SprintScope{}, fmt.Errorf("failed to get sprint scope: %w", err)
+	}
+
+	scope := model.SprintScope{Sprint: sprint, Tasks: make([]model.Task, 0)}
+	for _, task := range tasks {
+		if task.SprintId != sprint.Id {
+			continue
+		}
+
+		scope.Tasks = append(scope.Tasks, task)
+		scope.StoryPoints += task.StoryPoints
+		if task.Status == model.Done {
+			scope.DoneTasks++
+			scope.DoneStoryPoints += task.StoryPoints
+		}
+	}
+
+	scope.Completion = percentage(scope.DoneTasks, len(scope.Tasks))
+	scope.PointsCompletion = percentage(scope.DoneStoryPoints, scope.StoryPoints)
+	scope.CommittedTasks = len(sprintMembersAt(sprint, revisions, time.Time(sprint.StartAt)))
+
+	for _, change := range scopeChanges(sprint, revisions) {
+		if change.Type == model.ScopeAdded {
+			scope.AddedAfterStart++
+		} else {
+			scope.RemovedAfterStart++
+		}
+	}
+	return scope, nil
+}
+
+// GetScopeChanges lists the tasks added to or removed from the sprint between its start
+// and end, as recorded in the task history.
+func (s *SprintService) GetScopeChanges(sprintId int) ([]model.SprintScopeChange, error) {
+	sprint, err := s.GetSprint(sprintId)
+	if err != nil {
+		return nil, err
+	}
+
+	revisions, err := s.history.GetAllRevisions()
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to get history of sprint %d", sprintId), slog.Any("err", err))
+		return nil, fmt.Errorf("failed to get sprint scope changes: %w", err)
+	}
+	return scopeChanges(sprint, revisions), nil
+}
+
+func (s *SprintService) checkDates(sprint model.Sprint) error {
+	if !time.Time(sprint.EndAt).After(time.Time(sprint.StartAt)) {
+		err := errors.New("sprint must end after it starts")
+		s.log.Info(err.Error())
+		return invalidError(err, err.Error())
+	}
+	return nil
+}
+
+// scopeChanges replays revisions, which are in the order they were recorded, and returns
+// every time a task entered or left sprint while it was running.
+func scopeChanges(sprint model.Sprint, revisions []model.TaskRevision) []model.SprintScopeChange {
+	start, end := time.Time(sprint.StartAt), time.Time(sprint.EndAt)
+	inSprint := make(map[int]bool)
+	changes := make([]model.SprintScopeChange, 0)
+
+	for _, revision := range revisions {
+		was := inSprint[revision.TaskId]
+		is := revision.Type != model.TaskDeleted && revision.Task.SprintId == sprint.Id
+		inSprint[revision.TaskId] = is
+
+		at := time.Time(revision.At)
+		if was == is || at.Before(start) || at.After(end) {
+			continue
+		}
+
+		change := model.SprintScopeChange{
+			TaskId:  revision.TaskId,
+			Key:     revision.Task.Key,
+			Type:    model.ScopeAdded,
+			ActorId: revision.ActorId,
+			At:      revision.At,
+		}
+		if was {
+			change.Type = model.ScopeRemoved
+		}
+		changes = append(changes, change)
+	}
+	return changes
+}
+
+// sprintMembersAt returns the ids of the tasks that were in sprint at the instant at.
+func sprintMembersAt(sprint model.Sprint, revisions []model.TaskRevision, at time.Time) map[int]bool {
+	members := make(map[int]bool)
+	for _, revision := range revisions {
+		if time.Time(revision.At).After(at) {
+			break
+		}
+
+		if revision.Type != model.TaskDeleted && revision.Task.SprintId == sprint.Id {
+			members[revision.TaskId] = true
+		} else {
+			delete(members, revision.TaskId)
+		}
+	}
+	return members
+}
+
+// percentage returns part as a percentage of total rounded to one decimal, 0 when total is 0.
+func percentage(part int, total int) float64 {
+	if total == 0 {
+		return 0
+	}
+	return math.Round(float64(part)*1000/float64(total)) / 10
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+	"slices"
+	"testing"
+	"time"
+)
+
+// sprintRevisions are the revisions of the sprint 1 from 2026-03-02 to 2026-03-13: task 1
+// is planned before it starts, task 2 is added and then removed during it, task 3 is added
+// during it and deleted after it ends, and task 4 belongs to another sprint.
+func sprintRevisions(t *testing.T) (model.Sprint, []model.TaskRevision) {
+	at := func(value string) model.DateTime {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatalf("failed to parse time: %s", err)
+		}
+		return model.DateTime(parsed)
+	}
+
+	sprint := model.Sprint{Id: 1, StartAt: at("2026-03-02T00:00:00Z"), EndAt: at("2026-03-13T23:59:59Z")}
+	revisions := []model.TaskRevision{
+		{TaskId: 1, Type: model.TaskCreated, Task: model.Task{Id: 1, SprintId: 1, StoryPoints: 3}, At: at("2026-02-27T10:00:00Z")},
+		{TaskId: 2, Type: model.TaskCreated, Task: model.Task{Id: 2}, At: at("2026-02-27T11:00:00Z")},
+		{TaskId: 4, Type: model.TaskCreated, Task: model.Task{Id: 4, SprintId: 2}, At: at("2026-03-03T09:00:00Z")},
+		{TaskId: 2, Type: model.TaskUpdated, ActorId: 5, Task: model.Task{Id: 2, SprintId: 1}, At: at("2026-03-04T09:00:00Z")},
+		{TaskId: 3, Type: model.TaskCreated, ActorId: 6, Task: model.Task{Id: 3, SprintId: 1}, At: at("2026-03-05T09:00:00Z")},
+		{TaskId: 2, Type: model.TaskUpdated, ActorId: 5, Task: model.Task{Id: 2}, At: at("2026-03-09T09:00:00Z")},
+		{TaskId: 3, Type: model.TaskDeleted, Task: model.Task{Id: 3, SprintId: 1}, At: at("2026-03-16T09:00:00Z")},
+	}
+	return sprint, revisions
+}
+
+func TestScopeChanges(t *testing.T) {
+	sprint, revisions := sprintRevisions(t)
+
+	var answer []string
+	for _, change := range scopeChanges(sprint, revisions) {
+		answer = append(answer, fmt.Sprintf("%s %d by %d", change.Type, change.TaskId, change.ActorId))
+	}
+
+	// task 1 was planned and the deletion of task 3 happened after the sprint
+	expected := []string{
+		fmt.Sprintf("%s 2 by 5", model.ScopeAdded),
+		fmt.Sprintf("%s 3 by 6", model.ScopeAdded),
+		fmt.Sprintf("%s 2 by 5", model.ScopeRemoved),
+	}
+	if !slices.Equal(answer, expected) {
+		t.Errorf("got %v, but expected %v", answer, expected)
+	}
+}
+
+func TestSprintMembersAt(t *testing.T) {
+	sprint, revisions := sprintRevisions(t)
+
+	var testTable = []struct {
+		at       string
+		expected []int
+	}{
+		{"2026-02-26T00:00:00Z", []int{}},
+		{"2026-03-02T00:00:00Z", []int{1}},
+		{"2026-03-06T00:00:00Z", []int{1, 2, 3}},
+		{"2026-03-13T23:59:59Z", []int{1, 3}},
+		{"2026-03-20T00:00:00Z", []int{1}},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%s), Expect: %v", testData.at, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			at, _ := time.Parse(time.RFC3339, testData.at)
+			members := sprintMembersAt(sprint, revisions, at)
+
+			answer := make([]int, 0, len(members))
+			for id := range members {
+				answer = append(answer, id)
+			}
+			slices.Sort(answer)
+			if !slices.Equal(answer, testData.expected) {
+				t.Errorf("with input (%s) got %v, but expected %v", testData.at, answer, testData.expected)
+			}
+		})
+	}
+}
+
+func TestPercentage(t *testing.T) {
+
+	var testTable = []struct {
+		part     int
+		total    int
+		expected float64
+	}{
+		{0, 0, 0},
+		{3, 0, 0},
+		{0, 8, 0},
+		{1, 3, 33.3},
+		{2, 3, 66.7},
+		{8, 8, 100},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%d, %d), Expect: %v", testData.part, testData.total, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			if answer := percentage(testData.part, testData.total); answer != testData.expected {
+				t.Errorf("with input (%d, %d) got %v, but expected %v", testData.part, testData.total, answer, testData.expected)
+			}
+		})
+	}
+}
+
+func TestGetSprintScope_ZeroPoints(t *testing.T) {
+	const dirName = "Test_GetSprintScope_ZeroPoints"
+	defer removeTestDir(dirName)
+
+	repositories := newTestRepositories(t, dirName)
+	tasks := NewTaskService(repositories, Config{}, testLogger())
+	sprints := NewSprintService(repositories, testLogger())
+
+	start := time.Now().AddDate(0, 0, -1)
+	sprint, err := sprints.AddSprint(model.CreateSprint{Name: "Sprint 1", StartAt: model.DateTime(start), EndAt: model.DateTime(start.AddDate(0, 0, 14))})
+	if err != nil {
+		t.Fatalf("failed to add sprint: %s", err)
+	}
+
+	for _, status := range []model.TaskStatus{model.TODO, model.Done} {
+		if _, err := tasks.AddTask(model.CreateTask{Description: "Deploy", Status: status, SprintId: sprint.Id}, 0); err != nil {
+			t.Fatalf("failed to add task: %s", err)
+		}
+	}
+
+	scope, err := sprints.GetSprintScope(sprint.Id)
+	if err != nil {
+		t.Fatalf("expected GetSprintScope call to return no errors, got \"%s\"", err)
+	}
+
+	// tasks without story points leave the points completion at 0 instead of dividing by 0
+	if scope.Completion != 50 || scope.PointsCompletion != 0 || scope.StoryPoints != 0 {
+		t.Errorf("got completion %v and points completion %v of %d points, but expected 50 and 0 of 0 points", scope.Completion, scope.PointsCompletion, scope.StoryPoints)
+	}
+
+	// both tasks were added after the sprint started
+	if scope.CommittedTasks != 0 || scope.AddedAfterStart != 2 || scope.RemovedAfterStart != 0 {
+		t.Errorf("got %d committed, %d added and %d removed, but expected 0, 2 and 0", scope.CommittedTasks, scope.AddedAfterStart, scope.RemovedAfterStart)
+	}
+}
@@ -0,0 +1,323 @@
+package service
+
+import (
+	"cmp"
+	"fmt"
+	"go-task-tracker/model"
+	"log/slog"
+	"math"
+	"slices"
+	"time"
+)
+
+// Groups accepted by GetStats, along with GroupByStatus and GroupByTag.
+const (
+	GroupByAssignee = "assignee"
+	GroupByProject  = "project"
+)
+
+// Intervals of the series of GetStats.
+const (
+	IntervalDay  = "day"
+	IntervalWeek = "week"
+)
+
+const maxStatsIntervals = 1000
+
+// GetStats summarizes the tasks matching filter: how many there are, grouped by status,
+// tag, assignee or project unless groupBy is empty, and for the period from from and
+// before to, how many were created and completed per day or week along with the lead and
+// cycle times of those completed. Weeks start on Monday and dates are in filter.Location.
+// A zero to ends the period today and a zero from starts it 30 days or 12 weeks earlier.
+func (s *TaskService) GetStats(filter model.TaskFilter, groupBy string, interval string, from time.Time, to time.Time) (model.TaskStats, error) {
+	if !slices.Contains([]string{"", GroupByStatus, GroupByTag, GroupByAssignee, GroupByProject}, groupBy) {
+		err := fmt.Errorf("invalid group %q", groupBy)
+		s.log.Info(err.Error())
+		return model.TaskStats{}, invalidError(err, "stats can be grouped by status, tag, assignee or project")
+	}
+
+	if interval == "" {
+		interval = IntervalDay
+	}
+	if interval != IntervalDay && interval != IntervalWeek {
+		err := fmt.Errorf("invalid interval %q", interval)
+		s.log.Info(err.Error())
+		return model.TaskStats{}, invalidError(err, "stats are counted per day or week")
+	}
+
+	location := time.UTC
+	if filter.Location != nil {
+		location = filter.Location
+	}
+	from, to = statsPeriod(interval, from, to, location)
+
+	intervals := statsIntervals(interval, from, to)
+	if len(intervals) == 0 || len(intervals) > maxStatsIntervals {
+		err := fmt.Errorf("invalid period from %s to %s with %d intervals", from, to, len(intervals))
+		s.log.Info(err.Error())
+		return model.TaskStats{}, invalidError(err, fmt.Sprintf("the period must end after it starts and span at most %d intervals", maxStatsIntervals))
+	}
+
+	matcher, err := s.newTaskMatcher(filter)
+	if err != nil {
+		return model.TaskStats{}, err
+	}
+
+	tasks, err := s.findTasks(matcher)
+	if err != nil {
+		return model.TaskStats{}, fmt.Errorf("failed to get stats: %w", err)
+	}
+
+	// tasks created or completed in the period were completed after it started
+	archived, err := s.archive.GetArchivedTasks(from, time.Time{})
+	if err != nil {
+		s.log.Error("failed to get archived tasks for stats", slog.Any("err", err))
+		return model.TaskStats{}, fmt.Errorf("failed to get stats: %w", err)
+	}
+
+	all := slices.Clip(tasks)
+	for _, task := range archived {
+		if matcher.matches(task) {
+			all = append(all, task)
+		}
+	}
+
+	stats := model.TaskStats{
+		Tasks:     len(tasks),
+		Groups:    groupStats(tasks, groupBy),
+		Created:   countPerInterval(all, intervals, from, to, func(task model.Task) *model.DateTime { return &task.CreatedAt }),
+		Completed: countPerInterval(all, intervals, from, to, func(task model.Task) *model.DateTime { return task.CompletedAt }),
+	}
+
+	if stats.LeadTime, stats.CycleTime, err = s.flowTimes(all, from, to); err != nil {
+		return model.TaskStats{}, err
+	}
+	return stats, nil
+}
+
+// statsPeriod fills in the ends of the period from from and before to left zero.
+func statsPeriod(interval string, from time.Time, to time.Time, location *time.Location) (time.Time, time.Time) {
+	if to.IsZero() {
+		to = startOfDay(time.Now().In(location)).AddDate(0, 0, 1)
+	}
+
+	if from.IsZero() {
+		from = to.AddDate(0, 0, -30)
+		if interval == IntervalWeek {
+			from = intervalStart(IntervalWeek, to.AddDate(0, 0, -1)).AddDate(0, 0, -7*11)
+		}
+	}
+	return from, to
+}
+
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// intervalStart returns the start of the day or week holding t, in the location of t.
+func intervalStart(interval string, t time.Time) time.Time {
+	day := startOfDay(t)
+	if interval == IntervalWeek {
+		// weeks start on Monday
+		return day.AddDate(0, 0, -(int(day.Weekday())+6)%7)
+	}
+	return day
+}
+
+// statsIntervals returns the starts of the intervals covering the period from from and
+// before to, the first one starting on or before from.
+func statsIntervals(interval string, from time.Time, to time.Time) []time.Time {
+	days := 1
+	if interval == IntervalWeek {
+		days = 7
+	}
+
+	var starts []time.Time
+	for start := intervalStart(interval, from); start.Before(to); start = start.AddDate(0, 0, days) {
+		if len(starts) > maxStatsIntervals {
+			break
+		}
+		starts = append(starts, start)
+	}
+	return starts
+}
+
+// countPerInterval counts the tasks whose time of event falls in each of intervals, nil
+// times are left out.
+func countPerInterval(tasks []model.Task, intervals []time.Time, from time.Time, to time.Time, event func(model.Task) *model.DateTime) []model.StatsInterval {
+	counts := make([]model.StatsInterval, 0, len(intervals))
+	for _, start := range intervals {
+		counts = append(counts, model.StatsInterval{Start: start.Format(time.DateOnly)})
+	}
+
+	for _, task := range tasks {
+		at := event(task)
+		if at == nil || time.Time(*at).Before(from) || !time.Time(*at).Before(to) {
+			continue
+		}
+
+		// the last interval starting on or before the event holds it
+		t := time.Time(*at).In(from.Location())
+		i, found := slices.BinarySearchFunc(intervals, t, func(start time.Time, t time.Time) int { return start.Compare(t) })
+		if !found {
+			i--
+		}
+		counts[i].Tasks++
+	}
+	return counts
+}
+
+func groupStats(tasks []model.Task, groupBy string) []model.StatsGroup {
+	if groupBy == "" {
+		return nil
+	}
+
+	type groupKey struct {
+		status model.TaskStatus
+		tag    string
+		id     int
+	}
+	groups := make(map[groupKey]*model.StatsGroup)
+	add := func(key groupKey) {
+		group, ok := groups[key]
+		if !ok {
+			group = &model.StatsGroup{Tag: key.tag}
+			switch groupBy {
+			case GroupByStatus:
+				group.Status = &key.status
+			case GroupByAssignee:
+				group.AssigneeId = &key.id
+			case GroupByProject:
+				group.ProjectId = &key.id
+			}
+			groups[key] = group
+		}
+		group.Tasks++
+	}
+
+	for _, task := range tasks {
+		switch groupBy {
+		case GroupByStatus:
+			add(groupKey{status: task.Status})
+		case GroupByTag:
+			for _, tag := range task.Tags {
+				add(groupKey{tag: tag})
+			}
+		case GroupByAssignee:
+			add(groupKey{id: task.AssigneeId})
+		case GroupByProject:
+			add(groupKey{id: task.ProjectId})
+		}
+	}
+
+	result := make([]model.StatsGroup, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+
+	slices.SortFunc(result, func(a, b model.StatsGroup) int {
+		key := func(group model.StatsGroup) int {
+			switch {
+			case group.Status != nil:
+				return int(*group.Status)
+			case group.AssigneeId != nil:
+				return *group.AssigneeId
+			case group.ProjectId != nil:
+				return *group.ProjectId
+			}
+			return 0
+		}
+		return cmp.Or(cmp.Compare(key(a), key(b)), cmp.Compare(a.Tag, b.Tag))
+	})
+	return result
+}
+
+// flowTimes measures the lead and cycle times of the tasks completed from from and before
+// to. Tasks started before start times were recorded get theirs from the task history,
+// and tasks completed without being started have no cycle time.
+func (s *TaskService) flowTimes(tasks []model.Task, from time.Time, to time.Time) (model.DurationStats, model.DurationStats, error) {
+	var completed []model.Task
+	var unrecorded []int
+	for _, task := range tasks {
+		if task.CompletedAt == nil {
+			continue
+		}
+
+		completedAt := time.Time(*task.CompletedAt)
+		if !completedAt.Before(from) && completedAt.Before(to) {
+			completed = append(completed, task)
+			if task.StartedAt == nil {
+				unrecorded = append(unrecorded, task.Id)
+			}
+		}
+	}
+
+	var started map[int]time.Time
+	if len(unrecorded) > 0 {
+		var err error
+		if started, err = s.startTimes(unrecorded); err != nil {
+			return model.DurationStats{}, model.DurationStats{}, err
+		}
+	}
+
+	var leadTimes, cycleTimes []time.Duration
+	for _, task := range completed {
+		completedAt := time.Time(*task.CompletedAt)
+		leadTimes = append(leadTimes, completedAt.Sub(time.Time(task.CreatedAt)))
+
+		startedAt, ok := started[task.Id]
+		if task.StartedAt != nil {
+			startedAt, ok = time.Time(*task.StartedAt), true
+		}
+		if ok && !completedAt.Before(startedAt) {
+			cycleTimes = append(cycleTimes, completedAt.Sub(startedAt))
+		}
+	}
+	return durationStats(leadTimes), durationStats(cycleTimes), nil
+}
+
+// startTimes returns when each of the tasks taskIds first moved to InProgress according to
+// its history.
+func (s *TaskService) startTimes(taskIds []int) (map[int]time.Time, error) {
+	revisions, err := s.history.GetRevisionsOf(taskIds)
+	if err != nil {
+		s.log.Error("failed to get task history for stats", slog.Any("err", err))
+		return nil, fmt.Errorf("failed to get stats: %w", err)
+	}
+
+	started := make(map[int]time.Time)
+	for _, revision := range revisions {
+		at := time.Time(revision.At)
+		if first, ok := started[revision.TaskId]; revision.Task.Status == model.InProgress && (!ok || at.Before(first)) {
+			started[revision.TaskId] = at
+		}
+	}
+	return started, nil
+}
+
+// durationStats computes the average and nearest-rank percentiles of durations.
+func durationStats(durations []time.Duration) model.DurationStats {
+	if len(durations) == 0 {
+		return model.DurationStats{}
+	}
+	slices.Sort(durations)
+
+	var sum time.Duration
+	for _, duration := range durations {
+		sum += duration
+	}
+
+	percentile := func(p float64) model.Duration {
+		rank := int(math.Ceil(p / 100 * float64(len(durations))))
+		return model.Duration(durations[max(rank, 1)-1])
+	}
+
+	return model.DurationStats{
+		Tasks:   len(durations),
+		Average: model.Duration(sum / time.Duration(len(durations))),
+		P50:     percentile(50),
+		P75:     percentile(75),
+		P90:     percentile(90),
+		P95:     percentile(95),
+	}
+}
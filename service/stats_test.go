@@ -0,0 +1,140 @@
+package service
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDurationStats(t *testing.T) {
+
+	var testTable = []struct {
+		hours    []int
+		expected model.DurationStats
+	}{
+		{nil, model.DurationStats{}},
+		{[]int{5}, model.DurationStats{Tasks: 1, Average: hours(5), P50: hours(5), P75: hours(5), P90: hours(5), P95: hours(5)}},
+		{[]int{4, 1, 3, 2}, model.DurationStats{Tasks: 4, Average: hours(2) + hours(1)/2, P50: hours(2), P75: hours(3), P90: hours(4), P95: hours(4)}},
+		{[]int{10, 1, 2, 3, 4, 5, 6, 7, 8, 9}, model.DurationStats{Tasks: 10, Average: hours(5) + hours(1)/2, P50: hours(5), P75: hours(8), P90: hours(9), P95: hours(10)}},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%v), Expect: %+v", testData.hours, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			var durations []time.Duration
+			for _, h := range testData.hours {
+				durations = append(durations, time.Duration(h)*time.Hour)
+			}
+
+			if answer := durationStats(durations); answer != testData.expected {
+				t.Errorf("with input (%v) got %+v, but expected %+v", testData.hours, answer, testData.expected)
+			}
+		})
+	}
+}
+
+func TestCountPerInterval(t *testing.T) {
+	location, err := time.LoadLocation("Europe/Paris")
+	if err != nil {
+		t.Skipf("time zone database unavailable: %s", err)
+	}
+
+	at := func(value string) model.DateTime {
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			t.Fatalf("failed to parse time: %s", err)
+		}
+		return model.DateTime(parsed)
+	}
+
+	tasks := []model.Task{
+		{Id: 1, CreatedAt: at("2026-03-02T08:00:00Z")},
+		// Sunday evening in UTC is already Monday in Paris
+		{Id: 2, CreatedAt: at("2026-03-08T23:30:00Z")},
+		{Id: 3, CreatedAt: at("2026-03-10T12:00:00Z")},
+		{Id: 4, CreatedAt: at("2026-03-25T12:00:00Z")},
+		// before the period
+		{Id: 5, CreatedAt: at("2026-02-20T12:00:00Z")},
+	}
+
+	var testTable = []struct {
+		interval string
+		from     string
+		to       string
+		expected []model.StatsInterval
+	}{
+		{IntervalWeek, "2026-03-04", "2026-03-17", []model.StatsInterval{{Start: "2026-03-02", Tasks: 0}, {Start: "2026-03-09", Tasks: 2}, {Start: "2026-03-16", Tasks: 0}}},
+		{IntervalDay, "2026-03-08", "2026-03-11", []model.StatsInterval{{Start: "2026-03-08", Tasks: 0}, {Start: "2026-03-09", Tasks: 1}, {Start: "2026-03-10", Tasks: 1}}},
+		{IntervalWeek, "2026-03-23", "2026-03-30", []model.StatsInterval{{Start: "2026-03-23", Tasks: 1}}},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%s, %s, %s), Expect: %v", testData.interval, testData.from, testData.to, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			from, _ := time.ParseInLocation(time.DateOnly, testData.from, location)
+			to, _ := time.ParseInLocation(time.DateOnly, testData.to, location)
+
+			intervals := statsIntervals(testData.interval, from, to)
+			answer := countPerInterval(tasks, intervals, from, to, func(task model.Task) *model.DateTime { return &task.CreatedAt })
+			if !slices.Equal(answer, testData.expected) {
+				t.Errorf("with input (%s, %s, %s) got %v, but expected %v", testData.interval, testData.from, testData.to, answer, testData.expected)
+			}
+		})
+	}
+}
+
+func TestGroupStats(t *testing.T) {
+	tasks := []model.Task{
+		{Id: 1, Status: model.Done, Tags: []string{"b", "a"}, AssigneeId: 2},
+		{Id: 2, Status: model.TODO, Tags: []string{"a"}},
+		{Id: 3, Status: model.TODO, AssigneeId: 2, ProjectId: 1},
+	}
+
+	var testTable = []struct {
+		groupBy  string
+		expected string
+	}{
+		{"", "[]"},
+		{GroupByStatus, "[todo:2 done:1]"},
+		{GroupByTag, "[a:2 b:1]"},
+		{GroupByAssignee, "[0:1 2:2]"},
+		{GroupByProject, "[0:2 1:1]"},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%s), Expect: %s", testData.groupBy, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			var groups []string
+			for _, group := range groupStats(tasks, testData.groupBy) {
+				var key string
+				switch {
+				case group.Status != nil:
+					key = group.Status.Name()
+				case group.AssigneeId != nil:
+					key = fmt.Sprint(*group.AssigneeId)
+				case group.ProjectId != nil:
+					key = fmt.Sprint(*group.ProjectId)
+				default:
+					key = group.Tag
+				}
+				groups = append(groups, fmt.Sprintf("%s:%d", key, group.Tasks))
+			}
+
+			if answer := fmt.Sprintf("%v", groups); answer != testData.expected {
+				t.Errorf("with input (%s) got %s, but expected %s", testData.groupBy, answer, testData.expected)
+			}
+		})
+	}
+}
+
+func hours(n int) model.Duration {
+	return model.Duration(time.Duration(n) * time.Hour)
+}
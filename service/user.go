@@ -0,0 +1,97 @@
+package service
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-task-tracker/model"
+	"log/slog"
+)
+
+const tokenBytes = 32
+
+type UserService struct {
+	repository UserRepository
+	log        slog.Logger
+}
+
+func NewUserService(repository UserRepository, log *slog.Logger) UserService {
+	return UserService{repository: repository, log: *log}
+}
+
+// AddUser stores newUser and returns it along with the API token it must use to
+// authenticate. Only a hash of the token is kept, so it can't be retrieved again.
+func (s *UserService) AddUser(newUser model.CreateUser) (model.UserToken, error) {
+	if err := validateCreateUser(newUser); err != nil {
+		s.log.Info(fmt.Sprintf("invalid user: %s", err))
+		return model.UserToken{}, err
+	}
+
+	token, err := newToken()
+	if err != nil {
+		s.log.Error("failed to generate token", slog.Any("err", err))
+		return model.UserToken{}, NewError(err, "error when creating user")
+	}
+
+	user, err := s.repository.AddUser(model.User{
+		Name:      newUser.Name,
+		Email:     newUser.Email,
+		TokenHash: hashToken(token),
+		CreatedAt: model.Now(),
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to create user: %w", err)
+		s.log.Error(err.Error())
+		return model.UserToken{}, NewError(err, "error when creating user")
+	}
+
+	user.TokenHash = ""
+	return model.UserToken{User: user, Token: token}, nil
+}
+
+func (s *UserService) GetUsers() ([]model.User, error) {
+	users, err := s.repository.GetAllUsers()
+	if err != nil {
+		s.log.Error("failed to get users", slog.Any("err", err))
+		return nil, fmt.Errorf("failed to get users: %w", err)
+	}
+
+	for i := range users {
+		users[i].TokenHash = ""
+	}
+	return users, nil
+}
+
+// Authenticate returns the user owning token.
+func (s *UserService) Authenticate(token string) (model.User, error) {
+	users, err := s.repository.GetAllUsers()
+	if err != nil {
+		s.log.Error("failed to get users", slog.Any("err", err))
+		return model.User{}, fmt.Errorf("failed to authenticate: %w", err)
+	}
+
+	hash := []byte(hashToken(token))
+	for _, user := range users {
+		if subtle.ConstantTimeCompare(hash, []byte(user.TokenHash)) == 1 {
+			user.TokenHash = ""
+			return user, nil
+		}
+	}
+	return model.User{}, NewError(errors.New("no user found for token"), "invalid token")
+}
+
+func newToken() (string, error) {
+	b := make([]byte, tokenBytes)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to read random bytes: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
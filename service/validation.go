@@ -0,0 +1,193 @@
+package service
+
+import (
+	"fmt"
+	"go-task-tracker/model"
+	"go-task-tracker/search"
+	"net/mail"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	maxDescriptionLength   = 1000
+	maxTagLength           = 50
+	maxTags                = 20
+	maxChecklistItemLength = 500
+	maxChecklistItems      = 100
+	maxCommentLength       = 10000
+	maxNameLength          = 100
+	maxSearchLimit         = 100
+	defaultPageLimit       = 50
+	maxPageLimit           = 1000
+)
+
+// FieldError describes why the value of one input field was rejected. Field is the JSON
+// name of the field, with the index of the item for lists such as tags[2], and is empty for
+// errors about the input as a whole.
+type FieldError struct {
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+// ValidationError lists every invalid field of an input, so clients can point each
+// problem out at once.
+type ValidationError struct {
+	Fields []FieldError `json:"Errors"`
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, 0, len(e.Fields))
+	for _, field := range e.Fields {
+		messages = append(messages, fmt.Sprintf("%s %s", field.Field, field.Message))
+	}
+	return "invalid input: " + strings.Join(messages, ", ")
+}
+
+// Is makes a ValidationError match model.ErrValidation.
+func (e *ValidationError) Is(target error) bool {
+	return target == model.ErrValidation
+}
+
+// validator collects the field errors of an input.
+type validator struct {
+	fields []FieldError
+}
+
+func (v *validator) check(ok bool, field string, message string) {
+	if !ok {
+		v.fields = append(v.fields, FieldError{Field: field, Message: message})
+	}
+}
+
+func (v *validator) required(field string, value string) {
+	v.check(strings.TrimSpace(value) != "", field, "is required")
+}
+
+func (v *validator) maxLength(field string, value string, max int) {
+	v.check(utf8.RuneCountInString(value) <= max, field, fmt.Sprintf("must be at most %d characters", max))
+}
+
+func (v *validator) text(field string, value string, max int) {
+	v.required(field, value)
+	v.maxLength(field, value, max)
+}
+
+func (v *validator) status(field string, status model.TaskStatus) {
+	v.check(status.Valid(), field, "must be todo, in_progress or done")
+}
+
+func (v *validator) tags(field string, tags []string) {
+	v.check(len(tags) <= maxTags, field, fmt.Sprintf("must have at most %d tags", maxTags))
+	for i, tag := range tags {
+		v.maxLength(fmt.Sprintf("%s[%d]", field, i), tag, maxTagLength)
+	}
+}
+
+func (v *validator) id(field string, id int) {
+	v.check(id >= 0, field, "must not be negative")
+}
+
+// err returns the collected field errors, nil when the input is valid.
+func (v *validator) err() error {
+	if len(v.fields) == 0 {
+		return nil
+	}
+	return &ValidationError{Fields: v.fields}
+}
+
+func validateCreateTask(task model.CreateTask) error {
+	var v validator
+	v.text("description", task.Description, maxDescriptionLength)
+	v.status("status", task.Status)
+	v.id("assigneeId", task.AssigneeId)
+	v.id("sprintId", task.SprintId)
+	v.tags("tags", task.Tags)
+	v.check(len(task.Checklist) <= maxChecklistItems, "checklist", fmt.Sprintf("must have at most %d items", maxChecklistItems))
+	for i, item := range task.Checklist {
+		v.text(fmt.Sprintf("checklist[%d]", i), item, maxChecklistItemLength)
+	}
+	return v.err()
+}
+
+func validateUpdateTask(task model.UpdateTask) error {
+	var v validator
+	if task.Description != nil {
+		v.text("description", *task.Description, maxDescriptionLength)
+	}
+
+	if task.Status != nil {
+		v.status("status", *task.Status)
+	}
+
+	if task.AssigneeId != nil {
+		v.id("assigneeId", *task.AssigneeId)
+	}
+
+	if task.SprintId != nil {
+		v.id("sprintId", *task.SprintId)
+	}
+
+	if task.Tags != nil {
+		v.tags("tags", *task.Tags)
+	}
+	return v.err()
+}
+
+func validateChecklistItem(item model.CreateChecklistItem) error {
+	var v validator
+	v.text("text", item.Text, maxChecklistItemLength)
+	return v.err()
+}
+
+func validateComment(body string) error {
+	var v validator
+	v.text("body", body, maxCommentLength)
+	return v.err()
+}
+
+func validateCreateUser(user model.CreateUser) error {
+	var v validator
+	v.text("name", user.Name, maxNameLength)
+	v.required("email", user.Email)
+	if user.Email != "" {
+		_, err := mail.ParseAddress(user.Email)
+		v.check(err == nil, "email", "must be an email address")
+	}
+	return v.err()
+}
+
+func validateCreateProject(project model.CreateProject) error {
+	var v validator
+	v.text("name", project.Name, maxNameLength)
+	v.maxLength("description", project.Description, maxDescriptionLength)
+	v.id("defaultAssigneeId", project.DefaultAssigneeId)
+	return v.err()
+}
+
+func validateUpdateProject(project model.UpdateProject) error {
+	var v validator
+	if project.Name != nil {
+		v.text("name", *project.Name, maxNameLength)
+	}
+
+	if project.Description != nil {
+		v.maxLength("description", *project.Description, maxDescriptionLength)
+	}
+	return v.err()
+}
+
+func validateSearch(query string, limit int) error {
+	var v validator
+	v.check(len(search.Terms(query)) > 0, "q", "must have a word to search for")
+	v.check(limit >= 0 && limit <= maxSearchLimit, "limit", fmt.Sprintf("must be between 1 and %d, or 0 for the default of %d", maxSearchLimit, defaultSearchLimit))
+	return v.err()
+}
+
+func validatePage(page model.TaskPage, sort model.TaskSort) error {
+	var v validator
+	v.check(page.Limit >= 0 && page.Limit <= maxPageLimit, "limit", fmt.Sprintf("must be between 1 and %d, or 0 for the default of %d", maxPageLimit, defaultPageLimit))
+	_, err := decodeCursor(page.Cursor, sortKeys(sort))
+	v.check(err == nil, "cursor", "is not a cursor of this listing, it may have been made with another sort")
+	return v.err()
+}
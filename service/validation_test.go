@@ -0,0 +1,83 @@
+package service
+
+import (
+	"errors"
+	"fmt"
+	"go-task-tracker/model"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestValidateCreateTask(t *testing.T) {
+
+	var testTable = []struct {
+		name     string
+		task     model.CreateTask
+		expected []string
+	}{
+		{"valid", model.CreateTask{Description: "Write docs", Tags: []string{"docs"}}, nil},
+		{"blank description", model.CreateTask{Description: "  "}, []string{"description"}},
+		{"long description", model.CreateTask{Description: strings.Repeat("a", maxDescriptionLength+1)}, []string{"description"}},
+		{"unknown status", model.CreateTask{Description: "a", Status: 7}, []string{"status"}},
+		{"negative assignee", model.CreateTask{Description: "a", AssigneeId: -1}, []string{"assigneeId"}},
+		{"long tag", model.CreateTask{Description: "a", Tags: []string{"ok", strings.Repeat("t", maxTagLength+1)}}, []string{"tags[1]"}},
+		{"empty checklist item", model.CreateTask{Checklist: []string{"step", ""}}, []string{"description", "checklist[1]"}},
+	}
+
+	for _, testData := range testTable {
+
+		t.Run(testData.name, func(t *testing.T) {
+			err := validateCreateTask(testData.task)
+			if testData.expected == nil {
+				if err != nil {
+					t.Fatalf("expected no error, got %s", err)
+				}
+				return
+			}
+
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) {
+				t.Fatalf("expected a ValidationError, got %v", err)
+			}
+
+			fields := make([]string, 0, len(validationErr.Fields))
+			for _, field := range validationErr.Fields {
+				fields = append(fields, field.Field)
+			}
+			if !slices.Equal(fields, testData.expected) {
+				t.Errorf("got errors on fields %v, but expected %v", fields, testData.expected)
+			}
+		})
+	}
+}
+
+func TestValidateSearch(t *testing.T) {
+	limitError := []FieldError{{Field: "limit", Message: "must be between 1 and 100, or 0 for the default of 20"}}
+
+	var testTable = []struct {
+		limit    int
+		expected []FieldError
+	}{
+		{0, nil},
+		{100, nil},
+		{-1, limitError},
+		{101, limitError},
+	}
+
+	for _, testData := range testTable {
+
+		testName := fmt.Sprintf("For Input (%d), Expect: %v", testData.limit, testData.expected)
+
+		t.Run(testName, func(t *testing.T) {
+			var fields []FieldError
+			if err := validateSearch("deploy", testData.limit); err != nil {
+				fields = err.(*ValidationError).Fields
+			}
+
+			if !slices.Equal(fields, testData.expected) {
+				t.Errorf("with input (%d) got %v, but expected %v", testData.limit, fields, testData.expected)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package service
+
+import (
+	"cmp"
+	"fmt"
+	"go-task-tracker/model"
+	"log/slog"
+	"slices"
+	"time"
+)
+
+// Groups accepted by GetWorkLogTotals.
+const (
+	GroupByTask = "task"
+	GroupByUser = "user"
+	GroupByDate = "date"
+)
+
+// StartTimer starts logging the time userId spends on the task taskId. Users have at most
+// one running timer. With autoProgress a task in TODO is moved to InProgress.
+func (s *TaskService) StartTimer(taskId int, userId int, autoProgress bool) (model.WorkLog, error) {
+	task, err := s.findTask(taskId)
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to start timer on task %d: %s", taskId, err))
+		return model.WorkLog{}, NewError(err, "task does not exist")
+	}
+
+	s.workLogMutex.Lock()
+	defer s.workLogMutex.Unlock()
+
+	running, err := s.runningWorkLog(userId)
+	if err != nil {
+		return model.WorkLog{}, err
+	}
+
+	if running != nil {
+		err := fmt.Errorf("user %d already has a timer running on task %d", userId, running.TaskId)
+		s.log.Info(err.Error())
+		return model.WorkLog{}, conflictError(err, "a timer is already running")
+	}
+
+	// the task is moved before the timer is saved, so a move the workflow rejects leaves
+	// no timer running
+	if autoProgress && task.Status == model.TODO {
+		inProgress := model.InProgress
+		if _, err := s.UpdateTask(taskId, model.UpdateTask{Status: &inProgress}, userId); err != nil {
+			return model.WorkLog{}, err
+		}
+	}
+
+	workLog, err := s.workLogs.AddWorkLog(model.WorkLog{
+		TaskId:    taskId,
+		UserId:    userId,
+		StartedAt: model.Now(),
+		Running:   true,
+		CreatedAt: model.Now(),
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to start timer: %w", err)
+		s.log.Error(err.Error())
+		return model.WorkLog{}, NewError(err, "error when starting timer")
+	}
+	return workLog, nil
+}
+
+// StopTimer stops the timer userId is running on the task taskId and returns the logged work.
+func (s *TaskService) StopTimer(taskId int, userId int) (model.WorkLog, error) {
+	s.workLogMutex.Lock()
+	defer s.workLogMutex.Unlock()
+
+	running, err := s.runningWorkLog(userId)
+	if err != nil {
+		return model.WorkLog{}, err
+	}
+
+	if running == nil || running.TaskId != taskId {
+		err := fmt.Errorf("user %d has no timer running on task %d", userId, taskId)
+		s.log.Info(err.Error())
+		return model.WorkLog{}, conflictError(err, "no timer is running on this task")
+	}
+
+	workLog := *running
+	workLog.Running = false
+	workLog.Duration = model.Duration(time.Since(time.Time(workLog.StartedAt)).Round(time.Second))
+	if err := s.workLogs.UpdateWorkLog(workLog); err != nil {
+		err = fmt.Errorf("failed to stop timer: %w", err)
+		s.log.Error(err.Error())
+		return model.WorkLog{}, NewError(err, "error when stopping timer")
+	}
+
+	if err := s.reduceRemainingEstimate(taskId, workLog.Duration, userId); err != nil {
+		return model.WorkLog{}, err
+	}
+	return workLog, nil
+}
+
+// AddWorkLog records work done by userId on the task taskId without a timer.
+func (s *TaskService) AddWorkLog(taskId int, userId int, newWorkLog model.CreateWorkLog) (model.WorkLog, error) {
+	if _, err := s.findTask(taskId); err != nil {
+		s.log.Error(fmt.Sprintf("failed to log work on task %d: %s", taskId, err))
+		return model.WorkLog{}, NewError(err, "task does not exist")
+	}
+
+	if newWorkLog.Duration <= 0 {
+		err := fmt.Errorf("invalid work log duration %s", time.Duration(newWorkLog.Duration))
+		s.log.Info(err.Error())
+		return model.WorkLog{}, invalidError(err, "duration must be positive")
+	}
+
+	startedAt := model.DateTime(time.Now().UTC().Add(-time.Duration(newWorkLog.Duration)))
+	if newWorkLog.StartedAt != nil {
+		startedAt = *newWorkLog.StartedAt
+	}
+
+	workLog, err := s.workLogs.AddWorkLog(model.WorkLog{
+		TaskId:    taskId,
+		UserId:    userId,
+		StartedAt: startedAt,
+		Duration:  newWorkLog.Duration,
+		Note:      newWorkLog.Note,
+		CreatedAt: model.Now(),
+	})
+	if err != nil {
+		err = fmt.Errorf("failed to log work: %w", err)
+		s.log.Error(err.Error())
+		return model.WorkLog{}, NewError(err, "error when logging work")
+	}
+
+	if err := s.reduceRemainingEstimate(taskId, workLog.Duration, userId); err != nil {
+		return model.WorkLog{}, err
+	}
+	return workLog, nil
+}
+
+func (s *TaskService) GetWorkLogs(filter model.WorkLogFilter) ([]model.WorkLog, error) {
+	workLogs, err := s.workLogs.GetAllWorkLogs()
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to get work logs using filters %+v", filter), slog.Any("err", err))
+		return nil, fmt.Errorf("failed to get work logs: %w", err)
+	}
+
+	filtered := make([]model.WorkLog, 0)
+	for _, workLog := range workLogs {
+		if matchesWorkLogFilter(workLog, filter) {
+			filtered = append(filtered, workLog)
+		}
+	}
+	return filtered, nil
+}
+
+// GetWorkLogTotals sums the time logged in the entries matching filter, grouped by task,
+// user or date. An empty groupBy returns a single total. Running timers are not counted.
+func (s *TaskService) GetWorkLogTotals(filter model.WorkLogFilter, groupBy string) ([]model.WorkLogTotal, error) {
+	workLogs, err := s.GetWorkLogs(filter)
+	if err != nil {
+		return nil, err
+	}
+
+	location := time.UTC
+	if filter.Location != nil {
+		location = filter.Location
+	}
+
+	totals := make(map[model.WorkLogTotal]time.Duration)
+	for _, workLog := range workLogs {
+		if workLog.Running {
+			continue
+		}
+
+		var key model.WorkLogTotal
+		switch groupBy {
+		case GroupByTask:
+			key.TaskId = workLog.TaskId
+		case GroupByUser:
+			key.UserId = workLog.UserId
+		case GroupByDate:
+			key.Date = time.Time(workLog.StartedAt).In(location).Format(time.DateOnly)
+		case "":
+		default:
+			err := fmt.Errorf("invalid group %q", groupBy)
+			s.log.Info(err.Error())
+			return nil, invalidError(err, "work logs can be grouped by task, user or date")
+		}
+		totals[key] += time.Duration(workLog.Duration)
+	}
+
+	result := make([]model.WorkLogTotal, 0, len(totals))
+	for key, duration := range totals {
+		key.Duration = model.Duration(duration)
+		result = append(result, key)
+	}
+
+	slices.SortFunc(result, func(a, b model.WorkLogTotal) int {
+		return cmp.Or(cmp.Compare(a.TaskId, b.TaskId), cmp.Compare(a.UserId, b.UserId), cmp.Compare(a.Date, b.Date))
+	})
+	return result, nil
+}
+
+func (s *TaskService) runningWorkLog(userId int) (*model.WorkLog, error) {
+	workLogs, err := s.workLogs.GetAllWorkLogs()
+	if err != nil {
+		s.log.Error(fmt.Sprintf("failed to get work logs of user %d", userId), slog.Any("err", err))
+		return nil, fmt.Errorf("failed to get work logs: %w", err)
+	}
+
+	for _, workLog := range workLogs {
+		if workLog.Running && workLog.UserId == userId {
+			return &workLog, nil
+		}
+	}
+	return nil, nil
+}
+
+func matchesWorkLogFilter(workLog model.WorkLog, filter model.WorkLogFilter) bool {
+	startedAt := time.Time(workLog.StartedAt)
+
+	if filter.TaskId != 0 && workLog.TaskId != filter.TaskId {
+		return false
+	}
+
+	if filter.UserId != 0 && workLog.UserId != filter.UserId {
+		return false
+	}
+
+	if !filter.From.IsZero() && startedAt.Before(filter.From) {
+		return false
+	}
+
+	if !filter.To.IsZero() && !startedAt.Before(filter.To) {
+		return false
+	}
+
+	return true
+}